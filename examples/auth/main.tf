@@ -32,3 +32,15 @@ data "imply_permissions" "_" {}
 resource "imply_user" "user" {
   username = "foo2@bar.com"
 }
+
+resource "imply_group" "analysts" {
+  name = "Analysts"
+
+  permissions = [
+    { name = "AccessDataCubes" },
+    {
+      name      = "ManageDataCubes"
+      resources = ["projects/*"]
+    },
+  ]
+}
@@ -0,0 +1,7 @@
+data "imply_group_members" "analysts" {
+  group_id = imply_group.analysts.id
+}
+
+output "analyst_emails" {
+  value = data.imply_group_members.analysts.items[*].email
+}
@@ -0,0 +1,10 @@
+data "imply_user_effective_permissions" "contractor" {
+  email = "contractor@example.com"
+}
+
+check "contractor_is_not_admin" {
+  assert {
+    condition     = !contains(data.imply_user_effective_permissions.contractor.permission_names, "ManageDataCubes")
+    error_message = "Contractors must not hold ManageDataCubes."
+  }
+}
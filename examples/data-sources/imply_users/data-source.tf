@@ -0,0 +1,4 @@
+# Read at most 500 users instead of every user of the organization.
+data "imply_users" "first" {
+  limit = 500
+}
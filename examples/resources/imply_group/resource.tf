@@ -0,0 +1,11 @@
+resource "imply_group" "analysts" {
+  name = "Analysts"
+
+  permissions = [
+    { name = "AccessDataCubes" },
+    {
+      name      = "ManageDataCubes"
+      resources = ["projects/5xb1d143-f6x6-455x-x091-fbxa85xbx7x0"]
+    },
+  ]
+}
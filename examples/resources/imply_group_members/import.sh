@@ -0,0 +1,2 @@
+# Import the membership of a group by its group ID.
+terraform import imply_group_members.analysts 5xb1d143-f6x6-455x-x091-fbxa85xbx7x0
@@ -0,0 +1,7 @@
+resource "imply_group_members" "analysts" {
+  group_id = imply_group.analysts.id
+  user_ids = [
+    imply_user.alice.id,
+    imply_user.bob.id,
+  ]
+}
@@ -0,0 +1,10 @@
+resource "imply_user" "reporting_service" {
+  username = "reporting-service@example.com"
+
+  permissions = [
+    {
+      name      = "ManageDataCubes"
+      resources = ["projects/*"]
+    },
+  ]
+}
@@ -0,0 +1,4 @@
+# Import the group memberships of a user by the user's ID. Imported
+# memberships are authoritative until authoritative is set in the
+# configuration.
+terraform import imply_user_group_memberships.alice 16505d53-14c5-433a-84ca-00bbb9a2ae21
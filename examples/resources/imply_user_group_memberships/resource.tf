@@ -0,0 +1,8 @@
+resource "imply_user_group_memberships" "alice" {
+  user_id = imply_user.alice.id
+  group_ids = [
+    imply_group.analysts.id,
+    imply_group.on_call.id,
+  ]
+  authoritative = false
+}
@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// credentialExpiryDelta is how long before its expiry a cached access
+	// token or API key is refreshed, so that it cannot expire while a
+	// request is in flight.
+	credentialExpiryDelta = time.Minute
+
+	// credentialHelperTimeout bounds a single run of an API key command.
+	credentialHelperTimeout = 30 * time.Second
+)
+
+// Authenticator supplies the Authorization header of every API request.
+// Implementations must be safe for concurrent use.
+type Authenticator interface {
+	// Authorization returns the Authorization header value to send.
+	Authorization(ctx context.Context) (string, error)
+
+	// Secrets returns the credential values that must never appear in logs.
+	Secrets() []string
+}
+
+// APIKeyAuth authenticates with a Polaris API key.
+type APIKeyAuth struct {
+	Key string
+}
+
+// Authorization implements Authenticator.
+func (a *APIKeyAuth) Authorization(_ context.Context) (string, error) {
+	return "Basic " + a.Key, nil
+}
+
+// Secrets implements Authenticator.
+func (a *APIKeyAuth) Secrets() []string {
+	return []string{a.Key}
+}
+
+// APIKeyCommandAuth authenticates with an API key printed by an external
+// credential helper, in the manner of Docker and Kubernetes credential
+// helpers. The command must print a JSON object such as
+//
+//	{"key": "pok_...", "expiry": "2024-01-02T15:04:05Z"}
+//
+// where expiry is optional. The key is cached and the command run again
+// shortly before the key expires.
+type APIKeyCommandAuth struct {
+	// Command is the helper executable followed by its arguments. It is run
+	// directly, not through a shell.
+	Command []string
+
+	mu     sync.Mutex
+	key    string
+	expiry time.Time
+}
+
+// apiKeyCommandOutput is the JSON printed by an API key command.
+type apiKeyCommandOutput struct {
+	Key    string    `json:"key"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// Authorization implements Authenticator.
+func (a *APIKeyCommandAuth) Authorization(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.key == "" || (!a.expiry.IsZero() && time.Now().Add(credentialExpiryDelta).After(a.expiry)) {
+		if err := a.run(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return "Basic " + a.key, nil
+}
+
+// Secrets implements Authenticator.
+func (a *APIKeyCommandAuth) Secrets() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return []string{a.key}
+}
+
+// run executes the helper and caches the key it prints. The caller must
+// hold a.mu.
+func (a *APIKeyCommandAuth) run(ctx context.Context) error {
+	if len(a.Command) == 0 {
+		return errors.New("no API key command configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialHelperTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "Running API key command", map[string]any{
+		"command": a.Command[0],
+	})
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.Command[0], a.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("API key command %s failed: %w: %s", a.Command[0], err, message)
+		}
+		return fmt.Errorf("API key command %s failed: %w", a.Command[0], err)
+	}
+
+	var output apiKeyCommandOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return fmt.Errorf("API key command %s printed invalid JSON: %w", a.Command[0], err)
+	}
+
+	if output.Key == "" {
+		return fmt.Errorf("API key command %s printed no key", a.Command[0])
+	}
+
+	if !output.Expiry.IsZero() && !output.Expiry.After(time.Now()) {
+		return fmt.Errorf("API key command %s printed a key that expired at %s", a.Command[0], output.Expiry.Format(time.RFC3339))
+	}
+
+	a.key = output.Key
+	a.expiry = output.Expiry
+
+	return nil
+}
+
+// OAuthClientCredentials authenticates with a bearer token obtained from an
+// OAuth token endpoint through the client credentials grant. The token is
+// cached and refreshed shortly before it expires.
+type OAuthClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+
+	// HTTPClient sends the token requests. NewClient sets it to the API
+	// client's HTTP client when it is nil.
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// tokenResponse is the token endpoint's successful response.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenErrorResponse is the token endpoint's error response.
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Authorization implements Authenticator. Concurrent callers share a single
+// token request.
+func (a *OAuthClientCredentials) Authorization(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || (!a.expiry.IsZero() && time.Now().Add(credentialExpiryDelta).After(a.expiry)) {
+		if err := a.refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return "Bearer " + a.token, nil
+}
+
+// Secrets implements Authenticator.
+func (a *OAuthClientCredentials) Secrets() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	secrets := []string{a.ClientSecret}
+	if a.token != "" {
+		secrets = append(secrets, a.token)
+	}
+	return secrets
+}
+
+// refresh requests a new access token. The caller must hold a.mu.
+func (a *OAuthClientCredentials) refresh(ctx context.Context) error {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", a.ClientID)
+	form.Set("client_secret", a.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	tflog.Debug(ctx, "Requesting OAuth access token", map[string]any{
+		"token_url": a.TokenURL,
+		"client_id": a.ClientID,
+	})
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error requesting access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading token response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var tokenErr tokenErrorResponse
+		if json.Unmarshal(body, &tokenErr) == nil && tokenErr.Error != "" {
+			return fmt.Errorf("token endpoint returned %d %s: %s: %s",
+				resp.StatusCode, http.StatusText(resp.StatusCode), tokenErr.Error, tokenErr.ErrorDescription)
+		}
+		return fmt.Errorf("token endpoint returned %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("error unmarshaling token response: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return errors.New("token endpoint returned no access token")
+	}
+
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return fmt.Errorf("token endpoint returned unsupported token type %q", token.TokenType)
+	}
+
+	a.token = token.AccessToken
+	a.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		a.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return nil
+}
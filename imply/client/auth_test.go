@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tokenServer is a local OAuth token endpoint that issues numbered tokens
+// and records the form of every token request.
+type tokenServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	forms []map[string]string
+}
+
+func newTokenServer(t *testing.T, respond func(w http.ResponseWriter, issued int)) *tokenServer {
+	t.Helper()
+
+	s := &tokenServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("token request method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+			t.Errorf("token request Content-Type = %q", got)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+
+		form := map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+
+		s.mu.Lock()
+		s.forms = append(s.forms, form)
+		issued := len(s.forms)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		respond(w, issued)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *tokenServer) requests() []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]map[string]string(nil), s.forms...)
+}
+
+// bearerToken answers with token-N, valid for expiresIn seconds.
+func bearerToken(expiresIn int) func(http.ResponseWriter, int) {
+	return func(w http.ResponseWriter, issued int) {
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, issued, expiresIn)
+	}
+}
+
+func newTestOAuth(tokenURL string) *OAuthClientCredentials {
+	return &OAuthClientCredentials{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     tokenURL,
+	}
+}
+
+func TestOAuthClientCredentialsRequest(t *testing.T) {
+	server := newTokenServer(t, bearerToken(3600))
+	auth := newTestOAuth(server.URL)
+
+	got, err := auth.Authorization(context.Background())
+	if err != nil {
+		t.Fatalf("Authorization: %v", err)
+	}
+	if got != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token-1")
+	}
+
+	requests := server.requests()
+	if len(requests) != 1 {
+		t.Fatalf("token requests = %d, want 1", len(requests))
+	}
+
+	want := map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     "client-id",
+		"client_secret": "client-secret",
+	}
+	for key, value := range want {
+		if requests[0][key] != value {
+			t.Errorf("form %s = %q, want %q", key, requests[0][key], value)
+		}
+	}
+}
+
+func TestOAuthClientCredentialsCachesToken(t *testing.T) {
+	server := newTokenServer(t, bearerToken(3600))
+	auth := newTestOAuth(server.URL)
+
+	for range 3 {
+		got, err := auth.Authorization(context.Background())
+		if err != nil {
+			t.Fatalf("Authorization: %v", err)
+		}
+		if got != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want the cached %q", got, "Bearer token-1")
+		}
+	}
+
+	if got := len(server.requests()); got != 1 {
+		t.Errorf("token requests = %d, want 1", got)
+	}
+}
+
+func TestOAuthClientCredentialsRefreshesNearExpiry(t *testing.T) {
+	// A token expiring within credentialExpiryDelta is refreshed on every
+	// call.
+	expiresIn := int(credentialExpiryDelta.Seconds()) / 2
+	server := newTokenServer(t, bearerToken(expiresIn))
+	auth := newTestOAuth(server.URL)
+
+	for i := 1; i <= 2; i++ {
+		got, err := auth.Authorization(context.Background())
+		if err != nil {
+			t.Fatalf("Authorization: %v", err)
+		}
+		if want := fmt.Sprintf("Bearer token-%d", i); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+	}
+
+	if got := len(server.requests()); got != 2 {
+		t.Errorf("token requests = %d, want 2", got)
+	}
+}
+
+func TestOAuthClientCredentialsErrors(t *testing.T) {
+	tests := map[string]struct {
+		respond func(http.ResponseWriter, int)
+		wantErr string
+	}{
+		"error response": {
+			respond: func(w http.ResponseWriter, _ int) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Client authentication failed"}`))
+			},
+			wantErr: "token endpoint returned 401 Unauthorized: invalid_client: Client authentication failed",
+		},
+		"error without a body": {
+			respond: func(w http.ResponseWriter, _ int) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantErr: "token endpoint returned 502 Bad Gateway",
+		},
+		"non-bearer token": {
+			respond: func(w http.ResponseWriter, _ int) {
+				_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "mac", "expires_in": 3600}`))
+			},
+			wantErr: `unsupported token type "mac"`,
+		},
+		"missing access token": {
+			respond: func(w http.ResponseWriter, _ int) {
+				_, _ = w.Write([]byte(`{"token_type": "Bearer"}`))
+			},
+			wantErr: "token endpoint returned no access token",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newTokenServer(t, tt.respond)
+			auth := newTestOAuth(server.URL)
+
+			_, err := auth.Authorization(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Authorization error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOAuthClientCredentialsAuthenticatesAPIRequests(t *testing.T) {
+	tokens := newTokenServer(t, bearerToken(3600))
+
+	var gotAuthorization string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	c, err := NewClient(nil, nil, WithAPIURL(api.URL), WithAuthenticator(newTestOAuth(tokens.URL)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := c.Get(context.Background(), "/users"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if gotAuthorization != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want %q", gotAuthorization, "Bearer token-1")
+	}
+}
@@ -3,126 +3,482 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
+
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultTimeout bounds a call made with a context that has no deadline.
+	DefaultTimeout = 5 * time.Minute
+
+	// Request body content types. PATCH bodies built by the client are JSON
+	// merge patches.
+	contentTypeJSON       = "application/json"
+	contentTypeMergePatch = "application/merge-patch+json"
+
+	// requestIDHeader carries the identifier of a call, both in requests and
+	// in Polaris responses.
+	requestIDHeader = "X-Request-Id"
 )
 
+// userAgent builds the User-Agent header value.
+func userAgent(providerVersion, terraformVersion string) string {
+	if providerVersion == "" {
+		providerVersion = "dev"
+	}
+
+	agent := "terraform-provider-imply/" + providerVersion
+	if terraformVersion != "" {
+		agent += " terraform/" + terraformVersion
+	}
+	return agent
+}
+
 // Client represents the HTTP client for interacting with the API.
 type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
-	ApiKey     string
+
+	// Auth supplies the Authorization header of every request.
+	Auth Authenticator
+
+	// API is the typed Polaris API client generated from docs/openapi.json.
+	// Its requests are sent through Do.
+	API *polarisapi.ClientWithResponses
+
+	// MaxRetries is the number of times a throttled or failed request is
+	// retried before its error is returned. Zero disables retries.
+	MaxRetries int
+	// RetryMaxWait caps the wait between two attempts, including waits
+	// requested by the API through a Retry-After header.
+	RetryMaxWait time.Duration
+	// DefaultTimeout bounds a call, including its retries, when the caller's
+	// context carries no deadline of its own.
+	DefaultTimeout time.Duration
+
+	// RequestsPerSecond and MaxConcurrentRequests limit how fast and how
+	// many requests are sent. Zero means unlimited.
+	RequestsPerSecond     float64
+	MaxConcurrentRequests int
+
+	// UserAgent is sent with every request.
+	UserAgent string
+
+	// ReadOnly makes the client refuse every request that could change
+	// anything. See CheckWritable.
+	ReadOnly bool
+
+	// ProjectID is the project used by Project when a resource does not name
+	// one of its own.
+	ProjectID string
+	// RegionalURL is the base URL of the regional API serving ProjectID,
+	// including the /v1 prefix. It equals HostURL when no region is set.
+	RegionalURL string
+
+	apiURL    string
+	region    string
+	transport TransportConfig
+	limiter   *requestLimiter
+
+	credentials *CredentialInfo
+}
+
+// Option configures optional Client behaviour in NewClient.
+type Option func(*Client)
+
+// WithRetry overrides the default retry policy.
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+		c.RetryMaxWait = maxWait
+	}
+}
+
+// WithDefaultTimeout overrides the deadline applied to calls made without
+// one.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.DefaultTimeout = timeout
+	}
+}
+
+// WithProject sets the default project and the cloud region hosting it.
+// An empty region keeps project-scoped calls on the global host.
+func WithProject(projectID, region string) Option {
+	return func(c *Client) {
+		c.ProjectID = projectID
+		c.region = region
+	}
+}
+
+// WithAPIURL sets the API base URL explicitly, for custom domains and local
+// stand-ins. The URL is used as given, apart from an optional trailing /v1,
+// without the console-to-API or regional host rewriting applied to host.
+// host may be empty when it is set.
+func WithAPIURL(apiURL string) Option {
+	return func(c *Client) {
+		c.apiURL = apiURL
+	}
+}
+
+// WithAuthenticator authenticates requests with auth instead of an API key.
+// apiKey may be empty when it is set.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *Client) {
+		c.Auth = auth
+	}
+}
+
+// WithUserAgent identifies the provider and Terraform versions in the
+// User-Agent header. Empty versions are omitted.
+func WithUserAgent(providerVersion, terraformVersion string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent(providerVersion, terraformVersion)
+	}
+}
+
+// WithReadOnly makes the client refuse POST, PUT, PATCH and DELETE
+// requests.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) {
+		c.ReadOnly = readOnly
+	}
 }
 
 // NewClient creates and returns a new Client.
-func NewClient(host, apiKey *string) (*Client, error) {
-	if host == nil || *host == "" {
+func NewClient(host, apiKey *string, opts ...Option) (*Client, error) {
+	c := &Client{
+		HTTPClient:     &http.Client{},
+		UserAgent:      userAgent("", ""),
+		MaxRetries:     DefaultMaxRetries,
+		RetryMaxWait:   DefaultRetryMaxWait,
+		DefaultTimeout: DefaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transport, err := newTransport(c.transport)
+	if err != nil {
+		return nil, err
+	}
+	c.HTTPClient.Transport = transport
+
+	if c.Auth == nil {
+		if apiKey == nil || *apiKey == "" {
+			return nil, errors.New("apiKey cannot be nil or empty")
+		}
+		c.Auth = &APIKeyAuth{Key: *apiKey}
+	}
+
+	// Token requests share the API's transport.
+	if oauth, ok := c.Auth.(*OAuthClientCredentials); ok && oauth.HTTPClient == nil {
+		oauth.HTTPClient = c.HTTPClient
+	}
+
+	var baseURL string
+	switch {
+	case c.apiURL != "":
+		baseURL, err = normalizeAPIURL(c.apiURL)
+	case host != nil && *host != "":
+		baseURL, err = normalizeHost(*host)
+	default:
 		return nil, errors.New("host cannot be nil or empty")
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	if apiKey == nil || *apiKey == "" {
-		return nil, errors.New("apiKey cannot be nil or empty")
+	c.HostURL = baseURL + apiVersionPath
+	c.RegionalURL = c.HostURL
+	if c.apiURL == "" {
+		c.RegionalURL = regionalURL(c.HostURL, c.region)
 	}
 
-	hostURL := *host
-	// Replace .app.imply.io with .api.imply.io
-	hostURL = strings.Replace(hostURL, ".app.imply.io", ".api.imply.io", 1)
+	if c.MaxRetries < 0 {
+		return nil, errors.New("maxRetries cannot be negative")
+	}
 
-	// Ensure host URL ends with a slash
-	if !strings.HasSuffix(hostURL, "/") {
-		hostURL += "/"
+	if c.RetryMaxWait <= 0 {
+		return nil, errors.New("retryMaxWait must be positive")
 	}
 
-	return &Client{
-		HostURL:    hostURL + "v1",
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
-		ApiKey:     "Basic " + *apiKey,
-	}, nil
+	if c.DefaultTimeout <= 0 {
+		return nil, errors.New("defaultTimeout must be positive")
+	}
+
+	c.limiter, err = newRequestLimiter(c.RequestsPerSecond, c.MaxConcurrentRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := polarisapi.NewClientWithResponses(baseURL, polarisapi.WithHTTPClient(c))
+	if err != nil {
+		return nil, fmt.Errorf("error creating API client: %w", err)
+	}
+	c.API = api
+
+	return c, nil
+}
+
+// requestBody is the body of a request. A buffered body is logged and
+// replayed on retries, while a streamed body is opened afresh for every
+// attempt.
+type requestBody struct {
+	data []byte
+	open func() (io.ReadCloser, error)
+
+	// once marks a streamed body that can only be opened once, so that its
+	// request is never retried.
+	once bool
+}
+
+// reader returns the body of the next attempt, or nil when there is none.
+func (b requestBody) reader() (io.Reader, error) {
+	switch {
+	case b.open != nil:
+		return b.open()
+	case b.data != nil:
+		return bytes.NewReader(b.data), nil
+	default:
+		return nil, nil
+	}
 }
 
 // doRequest performs the actual HTTP request to the API.
-func (c *Client) doRequest(method, path string, body any) (map[string]any, error) {
-	// Prepare the request body if necessary
-	var reqBody io.Reader
+func (c *Client) doRequest(ctx context.Context, method, rawURL string, body any) (map[string]any, error) {
+	// Prepare the request body if necessary. The encoded body is kept so
+	// that it can be replayed on retries.
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		reqBody = strings.NewReader(string(jsonBody))
 	}
 
-	// Create the HTTP request
-	req, err := http.NewRequest(method, c.HostURL+path, reqBody)
+	contentType := contentTypeJSON
+	if method == http.MethodPatch {
+		contentType = contentTypeMergePatch
+	}
+
+	_, respBody, err := c.send(ctx, method, rawURL, contentType, nil, requestBody{data: jsonBody})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(respBody) == 0 {
+		return nil, nil
+	}
+
+	// Unmarshal the response JSON
+	var result map[string]any
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	return result, nil
+}
+
+// Do sends a request built by the generated Polaris API client through the
+// same authentication, retry and logging path as the untyped methods. It
+// implements polarisapi.HttpRequestDoer, and returns non-successful
+// responses as an *APIError instead of a response. Headers set by request
+// editors, such as If-Match, are sent along.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = contentTypeJSON
+	}
+
+	resp, respBody, err := c.send(req.Context(), req.Method, req.URL.String(), contentType, req.Header, requestBody{data: body})
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// send performs a request against an absolute URL and returns the response
+// along with its fully read body. Non-successful responses are returned as
+// an *APIError. header holds extra request headers and may be nil.
+func (c *Client) send(ctx context.Context, method, rawURL, contentType string, header http.Header, body requestBody) (*http.Response, []byte, error) {
+	if c.ReadOnly && !isSafeMethod(method) {
+		return nil, nil, fmt.Errorf("%w: refusing to send %s %s", ErrReadOnly, method, rawURL)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.DefaultTimeout)
+		defer cancel()
 	}
 
-	// Set the headers
-	req.Header.Set("Authorization", c.ApiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	// Every attempt of a call carries the same request ID, so that Polaris
+	// support can follow it across retries.
+	requestID := uuid.NewString()
+	ctx = tflog.SetField(c.loggingContext(ctx), "request_id", requestID)
 
-	// Execute the request
-	resp, err := c.HTTPClient.Do(req)
+	// Execute the request, retrying throttled and transient failures
+	resp, err := c.do(ctx, method, rawURL, contentType, header, body, requestID)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, nil, fmt.Errorf("%w\nRequest ID: %s", err, requestID)
 	}
 	defer resp.Body.Close()
 
 	// Read the response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return nil, nil, fmt.Errorf("error reading response: %w", err)
 	}
 
+	logResponseBody(ctx, method, resp.Request.URL.Path, respBody)
+
 	// Handle non-OK status codes
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		return nil, fmt.Errorf("status: %d, body: %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, newAPIError(method, resp.Request.URL.Path, resp, respBody)
 	}
 
-	if len(respBody) == 0 {
-		return nil, nil
-	}
+	return resp, respBody, nil
+}
 
-	// Unmarshal the response JSON
-	var result map[string]any
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+// do sends the request, retrying according to the client's retry policy. The
+// caller owns the body of the returned response.
+func (c *Client) do(ctx context.Context, method, rawURL, contentType string, header http.Header, body requestBody, requestID string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		reqBody, err := body.reader()
+		if err != nil {
+			return nil, err
+		}
+
+		// Create the HTTP request
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
+		if err != nil {
+			if closer, ok := reqBody.(io.Closer); ok {
+				closer.Close()
+			}
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		authorization, err := c.Auth.Authorization(ctx)
+		if err != nil {
+			closeRequestBody(req)
+			return nil, fmt.Errorf("error authenticating: %w", err)
+		}
+
+		// Set the headers, letting the client's own override the caller's
+		if header != nil {
+			req.Header = header.Clone()
+		}
+		req.Header.Set("Authorization", authorization)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", contentTypeJSON)
+		req.Header.Set("User-Agent", c.UserAgent)
+		req.Header.Set(requestIDHeader, requestID)
+
+		release, err := c.limiter.acquire(ctx)
+		if err != nil {
+			closeRequestBody(req)
+			return nil, fmt.Errorf("error waiting for the request rate limit: %w", err)
+		}
+
+		logRequest(ctx, req, body.data, attempt)
+
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		logResponse(ctx, req, resp, err, time.Since(start))
+		if ctx.Err() != nil {
+			// The operation was cancelled or ran out of time; there is no
+			// point in classifying the failure for a retry.
+			if resp != nil {
+				resp.Body.Close()
+			}
+			release()
+			return nil, fmt.Errorf("error making request: %w", ctx.Err())
+		}
+
+		wait := retryWait(attempt, resp, c.RetryMaxWait)
+		if attempt >= c.MaxRetries || body.once || !shouldRetry(method, resp, err) || !fitsDeadline(ctx, wait) {
+			if err != nil {
+				release()
+				return nil, fmt.Errorf("error making request: %w", err)
+			}
+			// The request slot is held until the caller has read the body.
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		release()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("error making request: %w", ctx.Err())
+		case <-timer.C:
+		}
 	}
+}
 
-	return result, nil
+// closeRequestBody closes the body of a request that will not be sent, so
+// that a streamed body stops being produced.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
 }
 
 // HTTP Methods for API interaction
 
 // Get performs a GET request to the specified path.
-func (c *Client) Get(path string) (map[string]any, error) {
-	return c.doRequest(http.MethodGet, path, nil)
+func (c *Client) Get(ctx context.Context, path string) (map[string]any, error) {
+	return c.doRequest(ctx, http.MethodGet, c.HostURL+path, nil)
 }
 
 // Post performs a POST request to the specified path with the given body.
-func (c *Client) Post(path string, body any) (map[string]any, error) {
-	return c.doRequest(http.MethodPost, path, body)
+func (c *Client) Post(ctx context.Context, path string, body any) (map[string]any, error) {
+	return c.doRequest(ctx, http.MethodPost, c.HostURL+path, body)
 }
 
 // Put performs a PUT request to the specified path with the given body.
-func (c *Client) Put(path string, body any) (map[string]any, error) {
-	return c.doRequest(http.MethodPut, path, body)
+func (c *Client) Put(ctx context.Context, path string, body any) (map[string]any, error) {
+	return c.doRequest(ctx, http.MethodPut, c.HostURL+path, body)
 }
 
 // Delete performs a DELETE request to the specified path.
-func (c *Client) Delete(path string) error {
-	_, err := c.doRequest(http.MethodDelete, path, nil)
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.doRequest(ctx, http.MethodDelete, c.HostURL+path, nil)
 	return err
 }
 
 // DeleteWithBody performs a DELETE request with a JSON body.
-func (c *Client) DeleteWithBody(path string, body any) (map[string]any, error) {
-	return c.doRequest(http.MethodDelete, path, body)
+func (c *Client) DeleteWithBody(ctx context.Context, path string, body any) (map[string]any, error) {
+	return c.doRequest(ctx, http.MethodDelete, c.HostURL+path, body)
 }
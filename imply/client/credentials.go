@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrMissingPermission is returned by RequirePermission when the validated
+// credentials lack a permission.
+var ErrMissingPermission = errors.New("the configured credentials lack a required permission")
+
+// CredentialInfo is the identity and permissions of the credentials the
+// client authenticates with, as reported by /v1/apikeyinfo.
+type CredentialInfo struct {
+	ID          string
+	Permissions []string
+}
+
+// ValidateCredentials checks the client's credentials against
+// /v1/apikeyinfo and caches what it returns for Credentials and
+// RequirePermission. It must be called before the client is shared.
+func (c *Client) ValidateCredentials(ctx context.Context) (*CredentialInfo, error) {
+	response, err := c.API.GetApiKeyInfoWithResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := response.JSON200
+	if keyInfo == nil {
+		if err := json.Unmarshal(response.Body, &keyInfo); err != nil {
+			return nil, fmt.Errorf("error unmarshaling API key info: %w", err)
+		}
+	}
+
+	info := &CredentialInfo{}
+	if keyInfo != nil {
+		if keyInfo.Id != nil {
+			info.ID = *keyInfo.Id
+		}
+		if keyInfo.Permissions != nil {
+			info.Permissions = *keyInfo.Permissions
+		}
+	}
+
+	c.credentials = info
+	return info, nil
+}
+
+// Credentials returns the credential information cached by
+// ValidateCredentials, or nil when the credentials were not validated.
+func (c *Client) Credentials() *CredentialInfo {
+	return c.credentials
+}
+
+// RequirePermission returns an error wrapping ErrMissingPermission when the
+// validated credentials do not hold permission. Without validated
+// credentials nothing is known, and nil is returned so that the API decides.
+func (c *Client) RequirePermission(permission string) error {
+	if c.credentials == nil || slices.Contains(c.credentials.Permissions, permission) {
+		return nil
+	}
+
+	held := "none"
+	if len(c.credentials.Permissions) > 0 {
+		held = strings.Join(c.credentials.Permissions, ", ")
+	}
+
+	return fmt.Errorf("%w: %s is required, but the credentials hold: %s", ErrMissingPermission, permission, held)
+}
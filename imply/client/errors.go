@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyLength bounds how much of an unparseable error body is kept
+// on an APIError.
+const maxErrorBodyLength = 512
+
+// APIError is returned for any non-successful response from the Polaris API.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method and Path identify the request that failed.
+	Method string
+	Path   string
+	// RequestID is the request identifier returned by Polaris, or else the
+	// one the client sent.
+	RequestID string
+
+	// Code, Message and Target are parsed from the Polaris ErrorResponse
+	// body when present.
+	Code    string
+	Message string
+	Target  string
+	// Details holds the nested error details of the ErrorResponse.
+	Details []APIErrorDetail
+
+	// Body is the raw response body, truncated, kept for responses that do
+	// not match the ErrorResponse schema.
+	Body string
+}
+
+// APIErrorDetail is a single entry of an ErrorResponse details list.
+type APIErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Target  string `json:"target"`
+}
+
+// errorResponse covers the ErrorResponse family of schemas as well as the
+// SqlErrorResponse shape used by the query API.
+type errorResponse struct {
+	Code       string           `json:"code"`
+	Message    string           `json:"message"`
+	Target     string           `json:"target"`
+	Details    []APIErrorDetail `json:"details"`
+	InnerError *APIErrorDetail  `json:"innererror"`
+
+	Error        string `json:"error"`
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s returned %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+
+	switch {
+	case e.Message != "" && e.Code != "":
+		fmt.Fprintf(&b, ": %s: %s", e.Code, e.Message)
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case e.Code != "":
+		fmt.Fprintf(&b, ": %s", e.Code)
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+
+	if e.Target != "" {
+		fmt.Fprintf(&b, " (target: %s)", e.Target)
+	}
+
+	for _, detail := range e.Details {
+		if detail.Message == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  - %s", detail.Message)
+		if detail.Target != "" {
+			fmt.Fprintf(&b, " (target: %s)", detail.Target)
+		}
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// newAPIError builds an APIError from a non-successful response and its
+// already-read body.
+func newAPIError(method, path string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		RequestID:  resp.Header.Get(requestIDHeader),
+	}
+
+	if apiErr.RequestID == "" && resp.Request != nil {
+		// Fall back to the ID the client sent when Polaris echoes none.
+		apiErr.RequestID = resp.Request.Header.Get(requestIDHeader)
+	}
+
+	var parsed errorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		apiErr.Code = firstNonEmpty(parsed.Code, parsed.ErrorCode)
+		apiErr.Message = firstNonEmpty(parsed.Message, parsed.ErrorMessage, parsed.Error)
+		apiErr.Target = parsed.Target
+		apiErr.Details = parsed.Details
+
+		if apiErr.Message == "" && parsed.InnerError != nil {
+			apiErr.Message = parsed.InnerError.Message
+		}
+	}
+
+	if apiErr.Code == "" && apiErr.Message == "" {
+		apiErr.Body = strings.TrimSpace(string(body))
+		if len(apiErr.Body) > maxErrorBodyLength {
+			apiErr.Body = apiErr.Body[:maxErrorBodyLength] + "..."
+		}
+	}
+
+	return apiErr
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// hasStatus reports whether err is or wraps an APIError with the given
+// status code.
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// IsNotFound reports whether err is a 404 response from the API.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a 409 response from the API.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsForbidden reports whether err is a 403 response from the API.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsUnauthorized reports whether err is a 401 response from the API.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsPreconditionFailed reports whether err is a 412 response from the API,
+// as returned when an If-Match entity tag no longer matches the object.
+func IsPreconditionFailed(err error) bool {
+	return hasStatus(err, http.StatusPreconditionFailed)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantCode    string
+		wantMessage string
+		wantBody    string
+		wantError   string
+	}{
+		{
+			name:        "error response",
+			status:      http.StatusNotFound,
+			body:        `{"code": "NotFound", "message": "User not found.", "target": "id"}`,
+			wantCode:    "NotFound",
+			wantMessage: "User not found.",
+			wantError:   "GET /v1/users/42 returned 404 Not Found: NotFound: User not found. (target: id)",
+		},
+		{
+			name:        "error response with details",
+			status:      http.StatusBadRequest,
+			body:        `{"code": "InvalidInput", "message": "The request is invalid.", "details": [{"code": "Invalid", "message": "username must be an email", "target": "username"}, {"code": "Empty"}]}`,
+			wantCode:    "InvalidInput",
+			wantMessage: "The request is invalid.",
+			wantError:   "GET /v1/users/42 returned 400 Bad Request: InvalidInput: The request is invalid.\n  - username must be an email (target: username)",
+		},
+		{
+			name:        "inner error message",
+			status:      http.StatusConflict,
+			body:        `{"code": "Conflict", "innererror": {"code": "Duplicate", "message": "A group with this name already exists."}}`,
+			wantCode:    "Conflict",
+			wantMessage: "A group with this name already exists.",
+			wantError:   "GET /v1/users/42 returned 409 Conflict: Conflict: A group with this name already exists.",
+		},
+		{
+			name:        "SQL error response",
+			status:      http.StatusBadRequest,
+			body:        `{"error": "Plan validation failed", "errorCode": "invalidInput", "errorMessage": "Column 'page' not found"}`,
+			wantCode:    "invalidInput",
+			wantMessage: "Column 'page' not found",
+			wantError:   "GET /v1/users/42 returned 400 Bad Request: invalidInput: Column 'page' not found",
+		},
+		{
+			name:      "non-JSON body",
+			status:    http.StatusBadGateway,
+			body:      "<html>Bad Gateway</html>\n",
+			wantBody:  "<html>Bad Gateway</html>",
+			wantError: "GET /v1/users/42 returned 502 Bad Gateway: <html>Bad Gateway</html>",
+		},
+		{
+			name:      "JSON without an error",
+			status:    http.StatusInternalServerError,
+			body:      `{"status": "down"}`,
+			wantBody:  `{"status": "down"}`,
+			wantError: `GET /v1/users/42 returned 500 Internal Server Error: {"status": "down"}`,
+		},
+		{
+			name:      "empty body",
+			status:    http.StatusUnauthorized,
+			wantError: "GET /v1/users/42 returned 401 Unauthorized",
+		},
+		{
+			name:     "long body is truncated",
+			status:   http.StatusInternalServerError,
+			body:     strings.Repeat("x", maxErrorBodyLength+100),
+			wantBody: strings.Repeat("x", maxErrorBodyLength) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+
+			apiErr := newAPIError(http.MethodGet, "/v1/users/42", resp, []byte(tt.body))
+
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", apiErr.Body, tt.wantBody)
+			}
+			if tt.wantError != "" && apiErr.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", apiErr.Error(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestNewAPIErrorRequestID(t *testing.T) {
+	sent := &http.Request{Header: http.Header{requestIDHeader: {"sent-id"}}}
+
+	tests := map[string]struct {
+		header http.Header
+		want   string
+	}{
+		"returned by Polaris": {header: http.Header{requestIDHeader: {"polaris-id"}}, want: "polaris-id"},
+		"sent by the client":  {header: http.Header{}, want: "sent-id"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusNotFound, Header: tt.header, Request: sent}
+
+			apiErr := newAPIError(http.MethodGet, "/v1/users/42", resp, nil)
+			if apiErr.RequestID != tt.want {
+				t.Errorf("RequestID = %q, want %q", apiErr.RequestID, tt.want)
+			}
+			if !strings.HasSuffix(apiErr.Error(), "\nRequest ID: "+tt.want) {
+				t.Errorf("Error() = %q, want it to end with the request ID", apiErr.Error())
+			}
+		})
+	}
+}
+
+func TestAPIErrorStatusHelpers(t *testing.T) {
+	helpers := map[string]func(error) bool{
+		"IsNotFound":           IsNotFound,
+		"IsConflict":           IsConflict,
+		"IsForbidden":          IsForbidden,
+		"IsUnauthorized":       IsUnauthorized,
+		"IsPreconditionFailed": IsPreconditionFailed,
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "404", err: &APIError{StatusCode: http.StatusNotFound}, want: "IsNotFound"},
+		{name: "wrapped 409", err: fmt.Errorf("creating group: %w", &APIError{StatusCode: http.StatusConflict}), want: "IsConflict"},
+		{name: "twice wrapped 403", err: fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", &APIError{StatusCode: http.StatusForbidden})), want: "IsForbidden"},
+		{name: "joined 401", err: errors.Join(errors.New("other"), &APIError{StatusCode: http.StatusUnauthorized}), want: "IsUnauthorized"},
+		{name: "412", err: &APIError{StatusCode: http.StatusPreconditionFailed}, want: "IsPreconditionFailed"},
+		{name: "500", err: &APIError{StatusCode: http.StatusInternalServerError}},
+		{name: "plain error mentioning 404", err: errors.New("status: 404")},
+		{name: "nil", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, helper := range helpers {
+				if got := helper(tt.err); got != (name == tt.want) {
+					t.Errorf("%s = %t, want %t", name, got, name == tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIErrorFromServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(requestIDHeader, "polaris-id")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "NotFound", "message": "User not found."}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+
+	t.Run("untyped", func(t *testing.T) {
+		_, err := c.Get(context.Background(), "/users/42")
+		if !IsNotFound(err) {
+			t.Fatalf("Get error = %v, want a 404 APIError", err)
+		}
+
+		want := "GET /v1/users/42 returned 404 Not Found: NotFound: User not found.\nRequest ID: polaris-id"
+		if err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		_, err := c.API.GetUserWithResponse(context.Background(), "42")
+		if !IsNotFound(err) {
+			t.Fatalf("GetUserWithResponse error = %v, want a 404 APIError", err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Message != "User not found." {
+			t.Errorf("error = %#v, want the parsed message", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+)
+
+// ETag returns the entity tag of a response, or an empty string when the
+// response carries none. Polaris does not return entity tags for every
+// object, so callers must treat an empty tag as "no concurrency control".
+func ETag(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get("ETag")
+}
+
+// IfMatch makes a generated client request conditional on the object still
+// having the entity tag etag, so that Polaris answers 412 Precondition
+// Failed instead of overwriting a change made elsewhere. An empty tag leaves
+// the request unconditional.
+func IfMatch(etag string) polarisapi.RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+)
+
+// etagServer serves a single group whose entity tag is current, answering
+// 412 to writes whose If-Match names another tag, and records the If-Match
+// header of every request.
+type etagServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	current  string
+	ifMatch  []string
+	hasMatch []bool
+}
+
+func newETagServer(t *testing.T, current string) *etagServer {
+	t.Helper()
+
+	s := &etagServer{current: current}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch, hasMatch := r.Header["If-Match"]
+
+		s.mu.Lock()
+		s.ifMatch = append(s.ifMatch, r.Header.Get("If-Match"))
+		s.hasMatch = append(s.hasMatch, hasMatch)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if hasMatch && ifMatch[0] != s.current {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"code": "PreconditionFailed", "message": "The group was modified."}`))
+			return
+		}
+
+		w.Header().Set("ETag", s.current)
+		_, _ = w.Write([]byte(`{"id": "42", "name": "analysts"}`))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// requests returns the If-Match header of every request, and whether it
+// was sent at all.
+func (s *etagServer) requests() ([]string, []bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.ifMatch...), append([]bool(nil), s.hasMatch...)
+}
+
+func TestETag(t *testing.T) {
+	server := newETagServer(t, `"v1"`)
+	c := newTestClient(t, server.URL)
+
+	response, err := c.API.GetGroupWithResponse(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("GetGroupWithResponse: %v", err)
+	}
+
+	if got := ETag(response.HTTPResponse); got != `"v1"` {
+		t.Errorf("ETag = %q, want %q", got, `"v1"`)
+	}
+	if got := ETag(&http.Response{Header: http.Header{}}); got != "" {
+		t.Errorf("ETag without a header = %q, want none", got)
+	}
+	if got := ETag(nil); got != "" {
+		t.Errorf("ETag(nil) = %q, want none", got)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	name := "analysts"
+	group := polarisapi.GroupRepresentation{Name: &name}
+
+	tests := []struct {
+		name         string
+		etag         string
+		write        func(c *Client, editor polarisapi.RequestEditorFn) error
+		wantIfMatch  bool
+		wantConflict bool
+	}{
+		{
+			name: "PUT with the current tag",
+			etag: `"v1"`,
+			write: func(c *Client, editor polarisapi.RequestEditorFn) error {
+				_, err := c.API.UpdateGroupWithResponse(context.Background(), "42", group, editor)
+				return err
+			},
+			wantIfMatch: true,
+		},
+		{
+			name: "PUT with a stale tag",
+			etag: `"v0"`,
+			write: func(c *Client, editor polarisapi.RequestEditorFn) error {
+				_, err := c.API.UpdateGroupWithResponse(context.Background(), "42", group, editor)
+				return err
+			},
+			wantIfMatch:  true,
+			wantConflict: true,
+		},
+		{
+			name: "DELETE with a stale tag",
+			etag: `"v0"`,
+			write: func(c *Client, editor polarisapi.RequestEditorFn) error {
+				_, err := c.API.DeleteGroupWithResponse(context.Background(), "42", editor)
+				return err
+			},
+			wantIfMatch:  true,
+			wantConflict: true,
+		},
+		{
+			name: "no tag leaves the request unconditional",
+			write: func(c *Client, editor polarisapi.RequestEditorFn) error {
+				_, err := c.API.UpdateGroupWithResponse(context.Background(), "42", group, editor)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newETagServer(t, `"v1"`)
+			c := newTestClient(t, server.URL)
+
+			err := tt.write(c, IfMatch(tt.etag))
+
+			if tt.wantConflict {
+				if !IsPreconditionFailed(err) {
+					t.Fatalf("error = %v, want a 412 APIError", err)
+				}
+			} else if err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			ifMatch, hasMatch := server.requests()
+			if len(ifMatch) != 1 {
+				t.Fatalf("requests = %d, want 1: a 412 must not be retried", len(ifMatch))
+			}
+			if hasMatch[0] != tt.wantIfMatch {
+				t.Errorf("If-Match sent = %t, want %t", hasMatch[0], tt.wantIfMatch)
+			}
+			if tt.wantIfMatch && ifMatch[0] != tt.etag {
+				t.Errorf("If-Match = %q, want %q", ifMatch[0], tt.etag)
+			}
+		})
+	}
+}
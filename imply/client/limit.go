@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit limits how fast the client sends requests. Every request
+// attempt, including retries, takes a token from a bucket refilled at
+// requestsPerSecond, and at most maxConcurrent requests are in flight at
+// once. Zero disables the corresponding limit. The limits are shared by
+// every resource and data source using the client.
+func WithRateLimit(requestsPerSecond float64, maxConcurrent int) Option {
+	return func(c *Client) {
+		c.RequestsPerSecond = requestsPerSecond
+		c.MaxConcurrentRequests = maxConcurrent
+	}
+}
+
+// requestLimiter enforces the client's rate and concurrency limits.
+type requestLimiter struct {
+	rate     *rate.Limiter
+	inFlight chan struct{}
+}
+
+// newRequestLimiter returns a limiter for the given limits, or nil when both
+// are disabled.
+func newRequestLimiter(requestsPerSecond float64, maxConcurrent int) (*requestLimiter, error) {
+	if requestsPerSecond < 0 || math.IsNaN(requestsPerSecond) || math.IsInf(requestsPerSecond, 0) {
+		return nil, errors.New("requestsPerSecond must be a finite, non-negative number")
+	}
+
+	if maxConcurrent < 0 {
+		return nil, errors.New("maxConcurrentRequests cannot be negative")
+	}
+
+	if requestsPerSecond == 0 && maxConcurrent == 0 {
+		return nil, nil
+	}
+
+	l := &requestLimiter{}
+	if requestsPerSecond > 0 {
+		// Allow bursts of up to one second's worth of requests, so that a
+		// fractional rate still lets a single request through.
+		burst := int(math.Max(1, math.Ceil(requestsPerSecond)))
+		l.rate = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+	if maxConcurrent > 0 {
+		l.inFlight = make(chan struct{}, maxConcurrent)
+	}
+
+	return l, nil
+}
+
+// acquire blocks until a request may be sent, and returns the function that
+// must be called once the request and its response body are done with.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			tflog.Trace(ctx, "Waiting for an Imply API request slot", map[string]any{
+				"max_concurrent_requests": cap(l.inFlight),
+			})
+			select {
+			case l.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-l.inFlight })
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// releaseOnClose releases a request slot once the response body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitCapsConcurrentRequests(t *testing.T) {
+	const maxConcurrent = 3
+
+	var inFlight, peak, total atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		total.Add(1)
+
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, WithRateLimit(0, maxConcurrent))
+
+	const goroutines = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(context.Background(), "/users"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Get: %v", err)
+	}
+
+	if got := total.Load(); got != goroutines {
+		t.Errorf("requests = %d, want %d", got, goroutines)
+	}
+	if got := peak.Load(); got > maxConcurrent {
+		t.Errorf("peak in-flight requests = %d, want at most %d", got, maxConcurrent)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Errorf("peak in-flight requests = %d, want requests to run concurrently", got)
+	}
+}
+
+func TestRateLimitReleasesSlotOnBodyClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(okHandler))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, WithRateLimit(0, 1))
+
+	get := func(ctx context.Context) (*http.Response, error) {
+		return c.do(ctx, http.MethodGet, c.HostURL+"/users", contentTypeJSON, nil, requestBody{}, "request-id")
+	}
+
+	resp, err := get(context.Background())
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	// The slot is still held while the first response body is open.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second request error = %v, want it to wait for the slot until its deadline", err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Closing twice must not release a second slot.
+	_ = resp.Body.Close()
+
+	resp, err = get(context.Background())
+	if err != nil {
+		t.Fatalf("request after Close: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := len(c.limiter.inFlight); got != 1 {
+		t.Errorf("slots in use = %d, want 1", got)
+	}
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(okHandler))
+	defer server.Close()
+
+	// A rate of 5 per second allows a burst of 5, after which each request
+	// waits 200ms for a token.
+	c := newTestClient(t, server.URL, WithRateLimit(5, 0))
+
+	start := time.Now()
+	for range 8 {
+		if _, err := c.Get(context.Background(), "/users"); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("8 requests took %s, want at least 500ms at 5 requests per second", elapsed)
+	}
+}
+
+func TestNewRequestLimiter(t *testing.T) {
+	if l, err := newRequestLimiter(0, 0); err != nil || l != nil {
+		t.Errorf("newRequestLimiter(0, 0) = %v, %v, want no limiter", l, err)
+	}
+
+	if _, err := newRequestLimiter(-1, 0); err == nil {
+		t.Error("newRequestLimiter(-1, 0) succeeded, want an error")
+	}
+
+	if _, err := newRequestLimiter(0, -1); err == nil {
+		t.Error("newRequestLimiter(0, -1) succeeded, want an error")
+	}
+
+	l, err := newRequestLimiter(0.5, 0)
+	if err != nil {
+		t.Fatalf("newRequestLimiter(0.5, 0): %v", err)
+	}
+	if got := l.rate.Burst(); got != 1 {
+		t.Errorf("burst for a fractional rate = %d, want 1", got)
+	}
+}
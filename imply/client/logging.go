@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// maxLoggedBodyLength bounds how much of a request or response body is
+	// written to the TRACE log.
+	maxLoggedBodyLength = 4096
+
+	redacted = "***"
+)
+
+// secretFields are JSON keys whose values are masked wherever they appear in
+// a logged body. They cover the credential-bearing properties of
+// docs/openapi.json: API key values, connection secrets and SSL keystores,
+// webhook authorization headers, embedding secrets and data cube filter
+// tokens, as well as OAuth tokens.
+var secretFields = map[string]bool{
+	"accesskeysecret": true,
+	"access_token":    true,
+	"apikey":          true,
+	"authheader":      true,
+	"client_secret":   true,
+	"embeddingsecret": true,
+	"filtertoken":     true,
+	"key":             true,
+	"keypassword":     true,
+	"keystore":        true,
+	"password":        true,
+	"refresh_token":   true,
+	"sastoken":        true,
+	"secret":          true,
+	"secrets":         true,
+}
+
+// secretHeaders are the request and response headers whose values are
+// masked in the log, in canonical form.
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+}
+
+// loggingContext returns a context whose log entries never contain the
+// client's credentials, even if they end up in a message or field by
+// accident.
+func (c *Client) loggingContext(ctx context.Context) context.Context {
+	var secrets []string
+	for _, secret := range c.Auth.Secrets() {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, secrets...)
+	return tflog.MaskMessageStrings(ctx, secrets...)
+}
+
+// logRequest logs an outgoing request. The body is only logged at TRACE.
+func logRequest(ctx context.Context, req *http.Request, body []byte, attempt int) {
+	fields := map[string]any{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"attempt": attempt + 1,
+	}
+
+	tflog.Debug(ctx, "Sending Imply API request", fields)
+
+	fields["headers"] = redactHeaders(req.Header)
+	if len(body) > 0 {
+		fields["body"] = redactBody(body)
+	}
+	tflog.Trace(ctx, "Imply API request details", fields)
+}
+
+// logResponse logs the outcome of a single request attempt.
+func logResponse(ctx context.Context, req *http.Request, resp *http.Response, err error, latency time.Duration) {
+	fields := map[string]any{
+		"method":     req.Method,
+		"url":        req.URL.String(),
+		"latency_ms": latency.Milliseconds(),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Imply API request failed", fields)
+		return
+	}
+
+	fields["status"] = resp.StatusCode
+	if requestID := resp.Header.Get(requestIDHeader); requestID != "" {
+		fields["response_request_id"] = requestID
+	}
+
+	tflog.Debug(ctx, "Received Imply API response", fields)
+}
+
+// logResponseBody logs a response body at TRACE.
+func logResponseBody(ctx context.Context, method, path string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	tflog.Trace(ctx, "Imply API response body", map[string]any{
+		"method": method,
+		"path":   path,
+		"body":   redactBody(body),
+	})
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// redactBody masks secret fields in a JSON body and truncates the result.
+// Bodies that are not JSON are logged as-is, truncated.
+func redactBody(body []byte) string {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if encoded, err := json.Marshal(redactValue(decoded)); err == nil {
+			body = encoded
+		}
+	}
+
+	if len(body) > maxLoggedBodyLength {
+		return string(body[:maxLoggedBodyLength]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if secretFields[strings.ToLower(key)] {
+				typed[key] = redacted
+				continue
+			}
+			typed[key] = redactValue(nested)
+		}
+		return typed
+	case []any:
+		for i, nested := range typed {
+			typed[i] = redactValue(nested)
+		}
+		return typed
+	default:
+		return value
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "top-level secret",
+			body: `{"name": "analysts", "apiKey": "pok_secret"}`,
+			want: `{"apiKey":"***","name":"analysts"}`,
+		},
+		{
+			name: "keys compared case-insensitively",
+			body: `{"APIKEY": "pok_secret", "Password": "hunter2"}`,
+			want: `{"APIKEY":"***","Password":"***"}`,
+		},
+		{
+			name: "nested connection secrets",
+			body: `{"type": "kafka", "secrets": {"type": "sasl_plain", "password": "hunter2"}, "ssl": {"truststore": {"certificates": "public"}, "keystore": {"key": "private", "keyPassword": "hunter2"}}}`,
+			want: `{"secrets":"***","ssl":{"keystore":"***","truststore":{"certificates":"public"}},"type":"kafka"}`,
+		},
+		{
+			name: "secrets inside arrays",
+			body: `{"values": [{"name": "alerts", "webhook": {"url": "https://hooks.example.com", "authHeader": "Bearer token"}}, {"name": "cube", "filterToken": "token"}]}`,
+			want: `{"values":[{"name":"alerts","webhook":{"authHeader":"***","url":"https://hooks.example.com"}},{"filterToken":"***","name":"cube"}]}`,
+		},
+		{
+			name: "top-level array",
+			body: `[{"accessKeySecret": "aws-secret", "accessKeyId": "AKIA"}]`,
+			want: `[{"accessKeyId":"AKIA","accessKeySecret":"***"}]`,
+		},
+		{
+			name: "secret objects are masked whole",
+			body: `{"embeddingSecret": {"value": "private"}}`,
+			want: `{"embeddingSecret":"***"}`,
+		},
+		{
+			name: "OAuth token response",
+			body: `{"access_token": "eyJ", "refresh_token": "eyR", "token_type": "Bearer"}`,
+			want: `{"access_token":"***","refresh_token":"***","token_type":"Bearer"}`,
+		},
+		{
+			name: "non-JSON body",
+			body: "grant_type=client_credentials",
+			want: "grant_type=client_credentials",
+		},
+		{
+			name: "JSON scalar",
+			body: `"pok_secret"`,
+			want: `"pok_secret"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactBodyTruncates(t *testing.T) {
+	tests := map[string]string{
+		"JSON":     `{"description": "` + strings.Repeat("x", maxLoggedBodyLength) + `"}`,
+		"non-JSON": strings.Repeat("x", maxLoggedBodyLength+1),
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := redactBody([]byte(body))
+			if !strings.HasSuffix(got, "...(truncated)") || len(got) != maxLoggedBodyLength+len("...(truncated)") {
+				t.Errorf("redactBody returned %d bytes, want %d bytes and a truncation marker", len(got), maxLoggedBodyLength)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{
+		"Authorization":       {"Basic cG9rX3NlY3JldDo="},
+		"Cookie":              {"session=abc", "csrf=def"},
+		"Proxy-Authorization": {"Basic cHJveHk6cGFzcw=="},
+		"Set-Cookie":          {"session=abc"},
+		"Content-Type":        {"application/json"},
+		"Accept":              {"application/json", "text/plain"},
+		"X-Request-Id":        {"request-id"},
+	}
+	// A header set without canonicalization is still recognized.
+	header["authorization"] = []string{"Bearer eyJ"}
+
+	want := map[string]string{
+		"Authorization":       redacted,
+		"authorization":       redacted,
+		"Cookie":              redacted,
+		"Proxy-Authorization": redacted,
+		"Set-Cookie":          redacted,
+		"Content-Type":        "application/json",
+		"Accept":              "application/json, text/plain",
+		"X-Request-Id":        "request-id",
+	}
+
+	got := redactHeaders(header)
+	if len(got) != len(want) {
+		t.Errorf("redactHeaders returned %d headers, want %d", len(got), len(want))
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("header %s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestRequestLogIsRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "ci", "apiKey": "pok_created"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c := newTestClient(t, server.URL)
+	if _, err := c.Post(ctx, "/apikeys", map[string]any{"name": "ci", "secrets": map[string]any{"password": "hunter2"}}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("MultilineJSONDecode: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("nothing was logged")
+	}
+
+	logged, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, secret := range []string{"test-key", "hunter2", "pok_created"} {
+		if strings.Contains(string(logged), secret) {
+			t.Errorf("log contains %q:\n%s", secret, logged)
+		}
+	}
+	for _, expected := range []string{"Sending Imply API request", "Received Imply API response", "request_id", `"status":200`} {
+		if !strings.Contains(string(logged), expected) {
+			t.Errorf("log does not contain %q:\n%s", expected, logged)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Pagination describes how a Polaris list endpoint pages its results.
+type Pagination struct {
+	// ItemsField is the response field holding the page's items.
+	ItemsField string
+	// LimitParam is the query parameter carrying the page size. Endpoints
+	// without one are fetched in a single request.
+	LimitParam string
+	// OffsetParam is the query parameter carrying the number of items to
+	// skip, for offset-style endpoints.
+	OffsetParam string
+	// CursorParam is the query parameter carrying the marker of the last
+	// item seen, for cursor-style endpoints, and CursorField is the item
+	// field the marker is read from.
+	CursorParam string
+	CursorField string
+	// PageSize is the number of items requested per page. It should not
+	// exceed the maximum the endpoint accepts.
+	PageSize int
+}
+
+// Pagination styles used by the Polaris list endpoints.
+var (
+	// PaginationTopSkip pages identity lists such as /users and
+	// /groups/{id}/members.
+	PaginationTopSkip = Pagination{ItemsField: "values", LimitParam: "top", OffsetParam: "skip", PageSize: 100}
+
+	// PaginationLimitOffset pages project file lists.
+	PaginationLimitOffset = Pagination{ItemsField: "files", LimitParam: "paginationLimit", OffsetParam: "paginationOffset", PageSize: 1000}
+
+	// PaginationSegmentCursor pages table segment lists, which continue
+	// from the ID of the last segment returned.
+	PaginationSegmentCursor = Pagination{ItemsField: "values", LimitParam: "limit", CursorParam: "lastSegmentId", CursorField: "id", PageSize: 100}
+
+	// PaginationNone reads lists that are returned whole, such as /groups
+	// and /permissions.
+	PaginationNone = Pagination{ItemsField: "values"}
+)
+
+// Paginate reads every page of the list endpoint at path and returns the
+// collected items. When limit is positive, reading stops once limit items
+// have been collected. query holds extra parameters, such as search filters,
+// sent with every page.
+func (c *Client) Paginate(ctx context.Context, path string, query url.Values, p Pagination, limit int) ([]map[string]any, error) {
+	return paginate(ctx, c.Get, path, query, p, limit)
+}
+
+// paginate implements Paginate on top of a GET function, so that global and
+// project-scoped clients share it.
+func paginate(ctx context.Context, get func(context.Context, string) (map[string]any, error), path string, query url.Values, p Pagination, limit int) ([]map[string]any, error) {
+	var items []map[string]any
+	var cursor string
+
+	for {
+		params := url.Values{}
+		for key, values := range query {
+			params[key] = values
+		}
+
+		pageSize := p.PageSize
+		if limit > 0 && (pageSize <= 0 || limit-len(items) < pageSize) {
+			pageSize = limit - len(items)
+		}
+
+		if p.LimitParam != "" && pageSize > 0 {
+			params.Set(p.LimitParam, strconv.Itoa(pageSize))
+		}
+		if p.OffsetParam != "" && len(items) > 0 {
+			params.Set(p.OffsetParam, strconv.Itoa(len(items)))
+		}
+		if p.CursorParam != "" && cursor != "" {
+			params.Set(p.CursorParam, cursor)
+		}
+
+		pagePath := path
+		if encoded := params.Encode(); encoded != "" {
+			pagePath += "?" + encoded
+		}
+
+		response, err := get(ctx, pagePath)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := pageItems(response, p.ItemsField)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		items = append(items, page...)
+
+		if limit > 0 && len(items) >= limit {
+			return items[:limit], nil
+		}
+
+		// Stop on a short page, or when the endpoint reports a total that
+		// has been reached. Endpoints without a page size parameter return
+		// everything at once.
+		if p.LimitParam == "" || len(page) == 0 || len(page) < pageSize {
+			return items, nil
+		}
+		if total, ok := response["count"].(float64); ok && len(items) >= int(total) {
+			return items, nil
+		}
+
+		if p.CursorParam != "" {
+			cursor = fmt.Sprintf("%v", page[len(page)-1][p.CursorField])
+		} else if p.OffsetParam == "" {
+			// Neither an offset nor a cursor can move past this page.
+			return items, nil
+		}
+	}
+}
+
+func pageItems(response map[string]any, field string) ([]map[string]any, error) {
+	raw, ok := response[field]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	values, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list in the %s field, got: %T", field, raw)
+	}
+
+	items := make([]map[string]any, 0, len(values))
+	for _, value := range values {
+		item, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected objects in the %s field, got: %T", field, value)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// listServer serves total numbered items from a list endpoint, paging them
+// by the given pagination style, and records the query of every request.
+type listServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func newListServer(t *testing.T, p Pagination, total int, withCount bool) *listServer {
+	t.Helper()
+
+	s := &listServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		s.mu.Lock()
+		s.queries = append(s.queries, query)
+		s.mu.Unlock()
+
+		start := 0
+		if p.OffsetParam != "" && query.Has(p.OffsetParam) {
+			start, _ = strconv.Atoi(query.Get(p.OffsetParam))
+		}
+		if p.CursorParam != "" && query.Has(p.CursorParam) {
+			last, _ := strconv.Atoi(query.Get(p.CursorParam))
+			start = last + 1
+		}
+
+		end := total
+		if p.LimitParam != "" && query.Has(p.LimitParam) {
+			size, _ := strconv.Atoi(query.Get(p.LimitParam))
+			end = min(start+size, total)
+		}
+
+		items := []map[string]any{}
+		for i := start; i < end; i++ {
+			items = append(items, map[string]any{"id": strconv.Itoa(i)})
+		}
+
+		response := map[string]any{p.ItemsField: items}
+		if withCount {
+			response["count"] = total
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *listServer) requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]url.Values(nil), s.queries...)
+}
+
+func itemIDs(items []map[string]any) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, fmt.Sprint(item["id"]))
+	}
+	return ids
+}
+
+func wantIDs(from, to int) []string {
+	var ids []string
+	for i := from; i < to; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	topSkip := Pagination{ItemsField: "values", LimitParam: "top", OffsetParam: "skip", PageSize: 10}
+	cursor := Pagination{ItemsField: "values", LimitParam: "limit", CursorParam: "lastSegmentId", CursorField: "id", PageSize: 10}
+
+	tests := []struct {
+		name       string
+		pagination Pagination
+		total      int
+		withCount  bool
+		limit      int
+		wantItems  int
+		// wantQueries are the paging parameters of each request, encoded.
+		wantQueries []string
+	}{
+		{
+			name:        "top and skip stop on a short page",
+			pagination:  topSkip,
+			total:       25,
+			wantItems:   25,
+			wantQueries: []string{"top=10", "skip=10&top=10", "skip=20&top=10"},
+		},
+		{
+			name:        "top and skip stop at the reported count",
+			pagination:  topSkip,
+			total:       20,
+			withCount:   true,
+			wantItems:   20,
+			wantQueries: []string{"top=10", "skip=10&top=10"},
+		},
+		{
+			name:        "top and skip read an empty last page",
+			pagination:  topSkip,
+			total:       20,
+			wantItems:   20,
+			wantQueries: []string{"top=10", "skip=10&top=10", "skip=20&top=10"},
+		},
+		{
+			name:        "cursor continues from the last item",
+			pagination:  cursor,
+			total:       15,
+			wantItems:   15,
+			wantQueries: []string{"limit=10", "lastSegmentId=9&limit=10"},
+		},
+		{
+			name:        "limit shrinks the last page and stops",
+			pagination:  topSkip,
+			total:       100,
+			limit:       15,
+			wantItems:   15,
+			wantQueries: []string{"top=10", "skip=10&top=5"},
+		},
+		{
+			name:        "limit smaller than a page",
+			pagination:  cursor,
+			total:       100,
+			limit:       3,
+			wantItems:   3,
+			wantQueries: []string{"limit=3"},
+		},
+		{
+			name:        "limit above the total",
+			pagination:  topSkip,
+			total:       5,
+			limit:       50,
+			wantItems:   5,
+			wantQueries: []string{"top=10"},
+		},
+		{
+			name:        "empty list",
+			pagination:  topSkip,
+			total:       0,
+			wantItems:   0,
+			wantQueries: []string{"top=10"},
+		},
+		{
+			name:        "unpaged list is read once",
+			pagination:  PaginationNone,
+			total:       250,
+			wantItems:   250,
+			wantQueries: []string{""},
+		},
+		{
+			name:        "unpaged list with a limit",
+			pagination:  PaginationNone,
+			total:       250,
+			limit:       7,
+			wantItems:   7,
+			wantQueries: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newListServer(t, tt.pagination, tt.total, tt.withCount)
+			c := newTestClient(t, server.URL)
+
+			items, err := c.Paginate(context.Background(), "/items", nil, tt.pagination, tt.limit)
+			if err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+
+			if got, want := itemIDs(items), wantIDs(0, tt.wantItems); !slices.Equal(got, want) {
+				t.Errorf("items = %v, want %v", got, want)
+			}
+
+			var queries []string
+			for _, query := range server.requests() {
+				queries = append(queries, query.Encode())
+			}
+			if !slices.Equal(queries, tt.wantQueries) {
+				t.Errorf("queries = %q, want %q", queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestPaginateKeepsQuery(t *testing.T) {
+	pagination := Pagination{ItemsField: "values", LimitParam: "top", OffsetParam: "skip", PageSize: 2}
+	server := newListServer(t, pagination, 3, false)
+	c := newTestClient(t, server.URL)
+
+	if _, err := c.Paginate(context.Background(), "/users", url.Values{"search": {"analyst"}}, pagination, 0); err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+
+	requests := server.requests()
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	for _, query := range requests {
+		if query.Get("search") != "analyst" {
+			t.Errorf("query = %s, want search=analyst on every page", query.Encode())
+		}
+	}
+}
+
+func TestProjectClientPaginate(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"files": [{"name": "wikipedia.json"}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, WithProject("project", ""))
+	project, err := c.Project("")
+	if err != nil {
+		t.Fatalf("Project: %v", err)
+	}
+
+	items, err := project.Paginate(context.Background(), "/files", nil, PaginationLimitOffset, 0)
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+
+	if gotPath != "/v1/projects/project/files" {
+		t.Errorf("path = %q, want %q", gotPath, "/v1/projects/project/files")
+	}
+	if len(items) != 1 || items[0]["name"] != "wikipedia.json" {
+		t.Errorf("items = %v, want the one file", items)
+	}
+}
+
+func TestPaginateUnexpectedItems(t *testing.T) {
+	tests := map[string]string{
+		"not a list":       `{"values": {"id": "1"}}`,
+		"not object items": `{"values": ["1", "2"]}`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			c := newTestClient(t, server.URL)
+			if _, err := c.Paginate(context.Background(), "/users", nil, PaginationTopSkip, 0); err == nil {
+				t.Fatal("Paginate succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import "reflect"
+
+// MergePatch returns the JSON merge patch (RFC 7396) that turns prior into
+// desired. Fields whose value differs are set to the desired value, fields
+// missing from desired are set to null, and nested objects are diffed field
+// by field. An empty patch means nothing changed.
+//
+// Both documents use the field names and JSON-compatible values of the API
+// representation, such as those built from a resource's state and plan.
+func MergePatch(prior, desired map[string]any) map[string]any {
+	patch := map[string]any{}
+
+	for key, value := range desired {
+		priorValue, ok := prior[key]
+		if !ok {
+			patch[key] = value
+			continue
+		}
+
+		priorObject, priorIsObject := priorValue.(map[string]any)
+		object, isObject := value.(map[string]any)
+		if priorIsObject && isObject {
+			if nested := MergePatch(priorObject, object); len(nested) > 0 {
+				patch[key] = nested
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(priorValue, value) {
+			patch[key] = value
+		}
+	}
+
+	for key := range prior {
+		if _, ok := desired[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	return patch
+}
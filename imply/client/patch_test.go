@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		prior   map[string]any
+		desired map[string]any
+		want    map[string]any
+	}{
+		{
+			name:    "unchanged documents",
+			prior:   map[string]any{"firstName": "Ada", "enabled": true, "tags": []any{"a", "b"}},
+			desired: map[string]any{"firstName": "Ada", "enabled": true, "tags": []any{"a", "b"}},
+			want:    map[string]any{},
+		},
+		{
+			name:    "empty documents",
+			prior:   nil,
+			desired: map[string]any{},
+			want:    map[string]any{},
+		},
+		{
+			name:    "changed fields only",
+			prior:   map[string]any{"firstName": "Ada", "lastName": "Byron", "enabled": true},
+			desired: map[string]any{"firstName": "Ada", "lastName": "Lovelace", "enabled": false},
+			want:    map[string]any{"lastName": "Lovelace", "enabled": false},
+		},
+		{
+			name:    "added field",
+			prior:   map[string]any{"firstName": "Ada"},
+			desired: map[string]any{"firstName": "Ada", "lastName": "Lovelace"},
+			want:    map[string]any{"lastName": "Lovelace"},
+		},
+		{
+			name:    "removed field is nulled",
+			prior:   map[string]any{"firstName": "Ada", "lastName": "Lovelace"},
+			desired: map[string]any{"firstName": "Ada"},
+			want:    map[string]any{"lastName": nil},
+		},
+		{
+			name:    "field set to null",
+			prior:   map[string]any{"lastName": "Lovelace"},
+			desired: map[string]any{"lastName": nil},
+			want:    map[string]any{"lastName": nil},
+		},
+		{
+			name:    "null left null",
+			prior:   map[string]any{"lastName": nil},
+			desired: map[string]any{"lastName": nil},
+			want:    map[string]any{},
+		},
+		{
+			name:    "null replaced by a value",
+			prior:   map[string]any{"lastName": nil},
+			desired: map[string]any{"lastName": "Lovelace"},
+			want:    map[string]any{"lastName": "Lovelace"},
+		},
+		{
+			name: "nested objects are diffed field by field",
+			prior: map[string]any{
+				"name": "events",
+				"settings": map[string]any{
+					"retention": "P30D",
+					"rollup":    true,
+					"partition": map[string]any{"granularity": "day", "clustering": "country"},
+				},
+			},
+			desired: map[string]any{
+				"name": "events",
+				"settings": map[string]any{
+					"retention": "P90D",
+					"rollup":    true,
+					"partition": map[string]any{"granularity": "day"},
+				},
+			},
+			want: map[string]any{
+				"settings": map[string]any{
+					"retention": "P90D",
+					"partition": map[string]any{"clustering": nil},
+				},
+			},
+		},
+		{
+			name:    "unchanged nested object is left out",
+			prior:   map[string]any{"settings": map[string]any{"retention": "P30D"}},
+			desired: map[string]any{"settings": map[string]any{"retention": "P30D"}},
+			want:    map[string]any{},
+		},
+		{
+			name:    "nested object replaced by a scalar",
+			prior:   map[string]any{"settings": map[string]any{"retention": "P30D"}},
+			desired: map[string]any{"settings": "default"},
+			want:    map[string]any{"settings": "default"},
+		},
+		{
+			name:    "nested object added",
+			prior:   map[string]any{"name": "events"},
+			desired: map[string]any{"name": "events", "settings": map[string]any{"retention": "P30D"}},
+			want:    map[string]any{"settings": map[string]any{"retention": "P30D"}},
+		},
+		{
+			name:    "arrays are replaced whole",
+			prior:   map[string]any{"tags": []any{"a", "b"}},
+			desired: map[string]any{"tags": []any{"a"}},
+			want:    map[string]any{"tags": []any{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergePatch(tt.prior, tt.desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergePatch(%v, %v) = %#v, want %#v", tt.prior, tt.desired, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ProjectClient issues requests against the project-scoped Polaris API,
+// prefixing every path with /v1/projects/{projectId} on the regional host.
+type ProjectClient struct {
+	client *Client
+
+	// ProjectID is the project every request is scoped to.
+	ProjectID string
+	// BaseURL is the regional project URL the request paths are appended to.
+	BaseURL string
+}
+
+// Project returns a client scoped to the given project. An empty projectID
+// selects the provider's default project, so resources can pass their own
+// optional project_id straight through.
+func (c *Client) Project(projectID string) (*ProjectClient, error) {
+	if projectID == "" {
+		projectID = c.ProjectID
+	}
+
+	if projectID == "" {
+		return nil, errors.New("no project ID was configured: set project_id on the provider")
+	}
+
+	return &ProjectClient{
+		client:    c,
+		ProjectID: projectID,
+		BaseURL:   c.RegionalURL + "/projects/" + url.PathEscape(projectID),
+	}, nil
+}
+
+// Get performs a GET request to the specified project-relative path.
+func (p *ProjectClient) Get(ctx context.Context, path string) (map[string]any, error) {
+	return p.client.doRequest(ctx, http.MethodGet, p.BaseURL+path, nil)
+}
+
+// Post performs a POST request to the specified project-relative path with
+// the given body.
+func (p *ProjectClient) Post(ctx context.Context, path string, body any) (map[string]any, error) {
+	return p.client.doRequest(ctx, http.MethodPost, p.BaseURL+path, body)
+}
+
+// Put performs a PUT request to the specified project-relative path with
+// the given body.
+func (p *ProjectClient) Put(ctx context.Context, path string, body any) (map[string]any, error) {
+	return p.client.doRequest(ctx, http.MethodPut, p.BaseURL+path, body)
+}
+
+// Patch performs a PATCH request to the specified project-relative path
+// with the given JSON merge patch.
+func (p *ProjectClient) Patch(ctx context.Context, path string, body any) (map[string]any, error) {
+	return p.client.doRequest(ctx, http.MethodPatch, p.BaseURL+path, body)
+}
+
+// Delete performs a DELETE request to the specified project-relative path.
+func (p *ProjectClient) Delete(ctx context.Context, path string) error {
+	_, err := p.client.doRequest(ctx, http.MethodDelete, p.BaseURL+path, nil)
+	return err
+}
+
+// Upload streams file as a multipart/form-data request to the specified
+// project-relative path. See Client.Upload.
+func (p *ProjectClient) Upload(ctx context.Context, method, path string, file UploadFile) (*UploadResult, error) {
+	return p.client.upload(ctx, method, p.BaseURL+path, file)
+}
+
+// Paginate reads every page of a project-scoped list endpoint. See
+// Client.Paginate.
+func (p *ProjectClient) Paginate(ctx context.Context, path string, query url.Values, pagination Pagination, limit int) ([]map[string]any, error) {
+	return paginate(ctx, p.Get, path, query, pagination, limit)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	apiKey := "test-key"
+	host := "example.app.imply.io"
+
+	tests := []struct {
+		name       string
+		defaultID  string
+		projectID  string
+		wantURL    string
+		wantErrMsg string
+	}{
+		{
+			name:      "falls back to the provider default",
+			defaultID: "default-project",
+			wantURL:   "https://example.us-east-1.aws.api.imply.io/v1/projects/default-project",
+		},
+		{
+			name:      "explicit project overrides the default",
+			defaultID: "default-project",
+			projectID: "other project",
+			wantURL:   "https://example.us-east-1.aws.api.imply.io/v1/projects/other%20project",
+		},
+		{
+			name:       "no project configured",
+			wantErrMsg: "no project ID was configured: set project_id on the provider",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&host, &apiKey, WithProject(tt.defaultID, "us-east-1"))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+
+			project, err := c.Project(tt.projectID)
+			if tt.wantErrMsg != "" {
+				if err == nil || err.Error() != tt.wantErrMsg {
+					t.Fatalf("Project error = %v, want %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Project: %v", err)
+			}
+
+			if project.BaseURL != tt.wantURL {
+				t.Errorf("BaseURL = %q, want %q", project.BaseURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestProjectRequestPath(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, WithProject("default-project", "us-east-1"))
+
+	project, err := c.Project("")
+	if err != nil {
+		t.Fatalf("Project: %v", err)
+	}
+
+	if _, err := project.Get(context.Background(), "/tables"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// An explicit api_url serves every scope itself, so the region is not
+	// added to its host.
+	if want := "/v1/projects/default-project/tables"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrReadOnly is returned for requests refused because the client is
+// read-only.
+var ErrReadOnly = errors.New("the Imply provider is configured as read_only")
+
+// CheckWritable returns an error wrapping ErrReadOnly when the client is
+// read-only. Resources call it before changing anything, so that they fail
+// before any request is sent.
+func (c *Client) CheckWritable() error {
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// isSafeMethod reports whether method only reads data.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+)
+
+func TestReadOnlyRefusesWrites(t *testing.T) {
+	server := newScriptedServer(t, scriptedResponse{status: http.StatusOK})
+	c := newTestClient(t, server.URL, WithReadOnly(true))
+	ctx := context.Background()
+
+	writes := map[string]func() error{
+		"POST": func() error {
+			_, err := c.Post(ctx, "/groups", map[string]any{"name": "analysts"})
+			return err
+		},
+		"PUT": func() error {
+			_, err := c.Put(ctx, "/groups/42", map[string]any{"name": "analysts"})
+			return err
+		},
+		"DELETE": func() error {
+			return c.Delete(ctx, "/groups/42")
+		},
+		"DELETE with a body": func() error {
+			_, err := c.DeleteWithBody(ctx, "/groups/42/members", []string{"7"})
+			return err
+		},
+		"PATCH": func() error {
+			_, err := c.doRequest(ctx, http.MethodPatch, c.HostURL+"/projects/42", map[string]any{"name": "analytics"})
+			return err
+		},
+		"upload": func() error {
+			_, err := c.Upload(ctx, http.MethodPost, "/projects/42/files", UploadFile{FileName: "events.json", Reader: strings.NewReader("{}")})
+			return err
+		},
+		"typed client": func() error {
+			name := "analysts"
+			_, err := c.API.CreateGroupWithResponse(ctx, polarisapi.GroupRepresentation{Name: &name})
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			err := write()
+			if !errors.Is(err, ErrReadOnly) {
+				t.Fatalf("error = %v, want ErrReadOnly", err)
+			}
+		})
+	}
+
+	if got := server.requests(); got != 0 {
+		t.Errorf("requests = %d, want none to reach the server", got)
+	}
+}
+
+func TestReadOnlyAllowsReads(t *testing.T) {
+	server := newScriptedServer(t, scriptedResponse{status: http.StatusOK})
+	c := newTestClient(t, server.URL, WithReadOnly(true))
+
+	if _, err := c.Get(context.Background(), "/groups"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if got := server.requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	readOnly := newTestClient(t, "http://localhost", WithReadOnly(true))
+	if err := readOnly.CheckWritable(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CheckWritable on a read-only client = %v, want ErrReadOnly", err)
+	}
+
+	writable := newTestClient(t, "http://localhost")
+	if err := writable.CheckWritable(); err != nil {
+		t.Errorf("CheckWritable on a writable client = %v, want nil", err)
+	}
+}
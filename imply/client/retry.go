@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried when no
+	// retry policy is configured.
+	DefaultMaxRetries = 4
+
+	// DefaultRetryMaxWait is the upper bound on a single wait between retries
+	// when no retry policy is configured.
+	DefaultRetryMaxWait = 30 * time.Second
+
+	retryBaseWait = 500 * time.Millisecond
+)
+
+// isIdempotent reports whether a request using the given method can be
+// repeated without side effects beyond those of the first attempt.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a request should be attempted again given the
+// method and the outcome of the previous attempt. A nil resp means the
+// request failed before a response was received.
+//
+// Non-idempotent requests such as POST are only retried on 429, where Polaris
+// has rejected the request before processing it.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return isIdempotent(method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// retryWait returns how long to wait before the given retry attempt
+// (starting at 0). A Retry-After header on resp takes precedence over the
+// jittered exponential backoff; both are capped at maxWait.
+func retryWait(attempt int, resp *http.Response, maxWait time.Duration) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, maxWait)
+		}
+	}
+
+	wait := retryBaseWait << attempt
+	if wait <= 0 || wait > maxWait {
+		wait = maxWait
+	}
+
+	// Full jitter over the upper half of the window keeps concurrent
+	// resources from retrying in lockstep.
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// fitsDeadline reports whether waiting for the given duration still leaves
+// the context's deadline in the future. Retrying into a deadline would only
+// replace the API error with a less useful context error.
+func fitsDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(wait).Before(deadline)
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedResponse is one response of a scriptedServer.
+type scriptedResponse struct {
+	status     int
+	retryAfter string
+}
+
+// scriptedServer answers each request with the next scripted response,
+// repeating the last one once the script runs out, and counts the requests
+// it receives.
+type scriptedServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	script   []scriptedResponse
+	attempts int
+}
+
+func newScriptedServer(t *testing.T, script ...scriptedResponse) *scriptedServer {
+	t.Helper()
+
+	s := &scriptedServer{script: script}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		response := s.script[min(s.attempts, len(s.script)-1)]
+		s.attempts++
+		s.mu.Unlock()
+
+		if response.retryAfter != "" {
+			w.Header().Set("Retry-After", response.retryAfter)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.status)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *scriptedServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.attempts
+}
+
+// newTestClient returns a client sending its requests to serverURL.
+func newTestClient(t *testing.T, serverURL string, opts ...Option) *Client {
+	t.Helper()
+
+	apiKey := "test-key"
+	c, err := NewClient(nil, &apiKey, append([]Option{WithAPIURL(serverURL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	return c
+}
+
+func TestRetryThrottledThenSucceeds(t *testing.T) {
+	server := newScriptedServer(t,
+		scriptedResponse{status: http.StatusTooManyRequests},
+		scriptedResponse{status: http.StatusOK},
+	)
+	c := newTestClient(t, server.URL, WithRetry(3, 10*time.Millisecond))
+
+	if _, err := c.Get(context.Background(), "/users"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if got := server.requests(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestRetryAfterIsCappedByRetryMaxWait(t *testing.T) {
+	tests := map[string]string{
+		"seconds":   "120",
+		"http date": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	}
+
+	for name, retryAfter := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newScriptedServer(t,
+				scriptedResponse{status: http.StatusTooManyRequests, retryAfter: retryAfter},
+				scriptedResponse{status: http.StatusOK},
+			)
+			maxWait := 50 * time.Millisecond
+			c := newTestClient(t, server.URL, WithRetry(1, maxWait))
+
+			start := time.Now()
+			if _, err := c.Get(context.Background(), "/users"); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			elapsed := time.Since(start)
+
+			if elapsed < maxWait {
+				t.Errorf("retried after %s, want at least RetryMaxWait %s", elapsed, maxWait)
+			}
+			if elapsed > 5*time.Second {
+				t.Errorf("retried after %s, want Retry-After capped at %s", elapsed, maxWait)
+			}
+			if got := server.requests(); got != 2 {
+				t.Errorf("requests = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", want: 0, wantOK: true},
+		{value: "7", want: 7 * time.Second, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, wantOK: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	if got := retryWait(0, resp, 2*time.Second); got != 2*time.Second {
+		t.Errorf("retryWait with Retry-After = %s, want it capped at 2s", got)
+	}
+
+	for attempt := range 10 {
+		got := retryWait(attempt, nil, 4*time.Second)
+		window := min(retryBaseWait<<attempt, 4*time.Second)
+		if got < window/2 || got > window {
+			t.Errorf("retryWait(%d) = %s, want within [%s, %s]", attempt, got, window/2, window)
+		}
+	}
+}
+
+func TestRetryNonIdempotentRequests(t *testing.T) {
+	t.Run("429", func(t *testing.T) {
+		server := newScriptedServer(t,
+			scriptedResponse{status: http.StatusTooManyRequests},
+			scriptedResponse{status: http.StatusOK},
+		)
+		c := newTestClient(t, server.URL, WithRetry(3, 10*time.Millisecond))
+
+		if _, err := c.Post(context.Background(), "/users", map[string]any{}); err != nil {
+			t.Fatalf("Post: %v", err)
+		}
+		if got := server.requests(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("503", func(t *testing.T) {
+		server := newScriptedServer(t,
+			scriptedResponse{status: http.StatusServiceUnavailable},
+			scriptedResponse{status: http.StatusOK},
+		)
+		c := newTestClient(t, server.URL, WithRetry(3, 10*time.Millisecond))
+
+		_, err := c.Post(context.Background(), "/users", map[string]any{})
+		if !hasStatus(err, http.StatusServiceUnavailable) {
+			t.Fatalf("Post error = %v, want a 503 APIError", err)
+		}
+		if got := server.requests(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+}
+
+func TestRetryStopsAfterMaxRetries(t *testing.T) {
+	server := newScriptedServer(t, scriptedResponse{status: http.StatusServiceUnavailable})
+	c := newTestClient(t, server.URL, WithRetry(2, 10*time.Millisecond))
+
+	_, err := c.Get(context.Background(), "/users")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Get error = %v, want a 503 APIError", err)
+	}
+	if got := server.requests(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestRetrySkippedWhenDeadlineWouldPass(t *testing.T) {
+	server := newScriptedServer(t,
+		scriptedResponse{status: http.StatusTooManyRequests, retryAfter: "10"},
+		scriptedResponse{status: http.StatusOK},
+	)
+	c := newTestClient(t, server.URL, WithRetry(3, time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Get(ctx, "/users")
+
+	if !hasStatus(err, http.StatusTooManyRequests) {
+		t.Fatalf("Get error = %v, want the 429 APIError rather than a context error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get returned after %s, want it to give up without waiting", elapsed)
+	}
+	if got := server.requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
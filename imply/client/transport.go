@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportConfig holds the TLS and proxy settings of the client's HTTP
+// transport. The zero value uses the system trust store and the proxy from
+// the HTTPS_PROXY and NO_PROXY environment variables.
+type TransportConfig struct {
+	// CACertFile and CACertPEM add PEM-encoded certificate authorities to
+	// the system trust store, for example those of a TLS-intercepting
+	// proxy.
+	CACertFile string
+	CACertPEM  string
+
+	// ClientCert and ClientKey are a PEM-encoded certificate and private
+	// key presented for mutual TLS. Each is either the PEM content or the
+	// path of a file holding it.
+	ClientCert string
+	ClientKey  string
+
+	// InsecureSkipVerify disables server certificate verification.
+	InsecureSkipVerify bool
+
+	// ProxyURL routes every request through the given proxy instead of the
+	// one from the environment.
+	ProxyURL string
+}
+
+// WithTransport configures the TLS and proxy settings of the HTTP transport.
+func WithTransport(config TransportConfig) Option {
+	return func(c *Client) {
+		c.transport = config
+	}
+}
+
+// newTransport builds an HTTP transport from config, starting from the
+// defaults of http.DefaultTransport.
+func newTransport(config TransportConfig) (*http.Transport, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACertFile != "" || config.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if config.CACertFile != "" {
+			pem, err := os.ReadFile(config.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in CA certificate file %s", config.CACertFile)
+			}
+		}
+
+		if config.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(config.CACertPEM)) {
+			return nil, errors.New("no PEM certificates found in CA certificate PEM")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, errors.New("a client certificate and a client key must be set together")
+		}
+
+		certPEM, err := pemContent(config.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client certificate: %w", err)
+		}
+
+		keyPEM, err := pemContent(config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client key: %w", err)
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", proxy.Redacted())
+		}
+
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxy.Redacted())
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
+
+// pemContent returns value if it holds PEM content, and otherwise reads the
+// file it names.
+func pemContent(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	return os.ReadFile(value)
+}
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClientCert is a self-signed client certificate and its key, both PEM
+// encoded.
+type testClientCert struct {
+	cert *x509.Certificate
+	pem  string
+	key  string
+}
+
+func newTestClientCert(t *testing.T) testClientCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform-provider-imply test client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	return testClientCert{
+		cert: cert,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		key:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// serverCAPEM returns the certificate of a TLS test server as PEM.
+func serverCAPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	return path
+}
+
+func okHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func TestTransportTrustsCACertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer server.Close()
+
+	tests := map[string]func(t *testing.T) TransportConfig{
+		"CACertPEM": func(t *testing.T) TransportConfig {
+			return TransportConfig{CACertPEM: serverCAPEM(server)}
+		},
+		"CACertFile": func(t *testing.T) TransportConfig {
+			return TransportConfig{CACertFile: writeTestFile(t, "ca.pem", serverCAPEM(server))}
+		},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, server.URL, WithTransport(config(t)), WithRetry(0, time.Second))
+
+			if _, err := c.Get(context.Background(), "/users"); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+		})
+	}
+
+	t.Run("untrusted", func(t *testing.T) {
+		c := newTestClient(t, server.URL, WithRetry(0, time.Second))
+
+		_, err := c.Get(context.Background(), "/users")
+		if err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("Get error = %v, want a certificate verification error", err)
+		}
+	})
+}
+
+func TestTransportMutualTLS(t *testing.T) {
+	clientCert := newTestClientCert(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(okHandler))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := map[string]func(t *testing.T) TransportConfig{
+		"PEM content": func(t *testing.T) TransportConfig {
+			return TransportConfig{
+				CACertPEM:  serverCAPEM(server),
+				ClientCert: clientCert.pem,
+				ClientKey:  clientCert.key,
+			}
+		},
+		"files": func(t *testing.T) TransportConfig {
+			return TransportConfig{
+				CACertPEM:  serverCAPEM(server),
+				ClientCert: writeTestFile(t, "client.pem", clientCert.pem),
+				ClientKey:  writeTestFile(t, "client-key.pem", clientCert.key),
+			}
+		},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, server.URL, WithTransport(config(t)), WithRetry(0, time.Second))
+
+			if _, err := c.Get(context.Background(), "/users"); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+		})
+	}
+
+	t.Run("without a client certificate", func(t *testing.T) {
+		c := newTestClient(t, server.URL, WithTransport(TransportConfig{CACertPEM: serverCAPEM(server)}), WithRetry(0, time.Second))
+
+		if _, err := c.Get(context.Background(), "/users"); err == nil {
+			t.Fatal("Get succeeded, want the server to reject the handshake")
+		}
+	})
+}
+
+func TestTransportProxyURL(t *testing.T) {
+	var gotURL, gotHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotHost = r.Host
+		okHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	c := newTestClient(t, "http://org.api.imply.invalid", WithTransport(TransportConfig{ProxyURL: proxy.URL}), WithRetry(0, time.Second))
+
+	if _, err := c.Get(context.Background(), "/users"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if want := "http://org.api.imply.invalid/v1/users"; gotURL != want {
+		t.Errorf("proxied URL = %q, want %q", gotURL, want)
+	}
+	if gotHost != "org.api.imply.invalid" {
+		t.Errorf("proxied Host = %q, want %q", gotHost, "org.api.imply.invalid")
+	}
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	clientCert := newTestClientCert(t)
+	otherCert := newTestClientCert(t)
+
+	tests := map[string]struct {
+		config  TransportConfig
+		wantErr string
+	}{
+		"mismatched certificate and key": {
+			config:  TransportConfig{ClientCert: clientCert.pem, ClientKey: otherCert.key},
+			wantErr: "error loading client certificate",
+		},
+		"certificate without a key": {
+			config:  TransportConfig{ClientCert: clientCert.pem},
+			wantErr: "a client certificate and a client key must be set together",
+		},
+		"missing certificate file": {
+			config:  TransportConfig{ClientCert: filepath.Join(t.TempDir(), "missing.pem"), ClientKey: clientCert.key},
+			wantErr: "error reading client certificate",
+		},
+		"CA PEM without certificates": {
+			config:  TransportConfig{CACertPEM: "not a certificate"},
+			wantErr: "no PEM certificates found in CA certificate PEM",
+		},
+		"proxy with an unsupported scheme": {
+			config:  TransportConfig{ProxyURL: "ftp://proxy.example.com"},
+			wantErr: "scheme must be http, https, socks5 or socks5h",
+		},
+		"proxy without a host": {
+			config:  TransportConfig{ProxyURL: "http://"},
+			wantErr: "missing host",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := newTransport(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newTransport error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
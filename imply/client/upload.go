@@ -0,0 +1,276 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultUploadField is the form field Polaris expects uploads in.
+	defaultUploadField = "file"
+
+	// defaultUploadContentType is the content type of an upload's file part
+	// when none is given.
+	defaultUploadContentType = "application/octet-stream"
+
+	// uploadProgressInterval is how many bytes are sent between progress log
+	// entries when the upload size is unknown.
+	uploadProgressInterval = 16 << 20
+)
+
+// UploadFile describes a file sent as a multipart/form-data upload, such as
+// to POST /v1/projects/{projectId}/files or PUT
+// /v1/customizations/logos/{kind}. The file is streamed, never held in
+// memory as a whole.
+type UploadFile struct {
+	// FieldName is the form field holding the file. Defaults to "file".
+	FieldName string
+	// FileName is the file name sent to the API. Defaults to the base name
+	// of Path.
+	FileName string
+
+	// Path is the file to upload. Exactly one of Path and Reader must be
+	// set. A file is read from the start again when a request is retried.
+	Path string
+	// Reader supplies the content instead of Path. It can only be read once,
+	// so uploads from a reader are never retried.
+	Reader io.Reader
+	// Size is the length of the content read from Reader, used only to log
+	// progress. Zero means unknown.
+	Size int64
+
+	// ContentType is the content type of the file part. Defaults to
+	// application/octet-stream.
+	ContentType string
+	// Fields are additional form fields, sent before the file.
+	Fields map[string]string
+}
+
+// UploadResult is the outcome of a successful upload.
+type UploadResult struct {
+	// Response is the decoded JSON response, or nil when it has no body.
+	Response map[string]any
+	// Bytes is the number of file bytes sent.
+	Bytes int64
+	// SHA256 is the hex-encoded SHA-256 checksum of the bytes sent, to be
+	// compared with what the API reports having stored.
+	SHA256 string
+}
+
+// Upload streams file as a multipart/form-data request to the specified
+// path.
+func (c *Client) Upload(ctx context.Context, method, path string, file UploadFile) (*UploadResult, error) {
+	return c.upload(ctx, method, c.HostURL+path, file)
+}
+
+// upload streams file as a multipart/form-data request to an absolute URL.
+func (c *Client) upload(ctx context.Context, method, rawURL string, file UploadFile) (*UploadResult, error) {
+	if (file.Path == "") == (file.Reader == nil) {
+		return nil, errors.New("exactly one of an upload's path and reader must be set")
+	}
+
+	if file.FieldName == "" {
+		file.FieldName = defaultUploadField
+	}
+	if file.FileName == "" {
+		file.FileName = filepath.Base(file.Path)
+	}
+	if file.ContentType == "" {
+		file.ContentType = defaultUploadContentType
+	}
+
+	if file.Path != "" {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading upload file: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("upload file %s is not a regular file", file.Path)
+		}
+		file.Size = info.Size()
+	}
+
+	// Every attempt must use the same boundary as the content type header.
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	contentType := "multipart/form-data; boundary=" + boundary
+
+	tflog.Debug(ctx, "Uploading file to the Imply API", map[string]any{
+		"file_name":    file.FileName,
+		"content_type": file.ContentType,
+		"size":         file.Size,
+	})
+
+	var last *uploadAttempt
+	body := requestBody{
+		open: func() (io.ReadCloser, error) {
+			source := file.Reader
+			if file.Path != "" {
+				f, err := os.Open(file.Path)
+				if err != nil {
+					return nil, fmt.Errorf("error opening upload file: %w", err)
+				}
+				source = f
+			}
+
+			last = startUpload(ctx, file, source, boundary)
+			return last.body, nil
+		},
+		once: file.Path == "",
+	}
+
+	_, respBody, err := c.send(ctx, method, rawURL, contentType, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UploadResult{}
+	result.Bytes, result.SHA256 = last.wait()
+
+	tflog.Debug(ctx, "Uploaded file to the Imply API", map[string]any{
+		"file_name": file.FileName,
+		"bytes":     result.Bytes,
+		"sha256":    result.SHA256,
+	})
+
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &result.Response); err != nil {
+			return nil, fmt.Errorf("error unmarshaling response: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// uploadAttempt writes the multipart body of a single request attempt into
+// a pipe from a separate goroutine, so that the file is streamed as the
+// transport reads it.
+type uploadAttempt struct {
+	body *io.PipeReader
+	done chan struct{}
+
+	// bytes and sum are only read once done is closed.
+	bytes int64
+	sum   hash.Hash
+}
+
+// startUpload starts writing the multipart body of file, whose content is
+// read from source, and returns the attempt whose body the request sends.
+func startUpload(ctx context.Context, file UploadFile, source io.Reader, boundary string) *uploadAttempt {
+	pr, pw := io.Pipe()
+	a := &uploadAttempt{body: pr, done: make(chan struct{}), sum: sha256.New()}
+
+	go func() {
+		defer close(a.done)
+		if closer, ok := source.(io.Closer); ok && file.Path != "" {
+			defer closer.Close()
+		}
+
+		// Closing the pipe with the write error, or nil, ends the request
+		// body; a failed request closes the reader, which ends the writes.
+		pw.CloseWithError(a.write(ctx, pw, file, source, boundary))
+	}()
+
+	return a
+}
+
+// write writes the multipart body to w.
+func (a *uploadAttempt) write(ctx context.Context, w io.Writer, file UploadFile, source io.Reader, boundary string) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(file.Fields))
+	for name := range file.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := mw.WriteField(name, file.Fields[name]); err != nil {
+			return err
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(file.FieldName), escapeQuotes(file.FileName)))
+	header.Set("Content-Type", file.ContentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	progress := &progressWriter{ctx: ctx, fileName: file.FileName, size: file.Size, count: &a.bytes}
+	if _, err := io.Copy(io.MultiWriter(part, a.sum, progress), source); err != nil {
+		return fmt.Errorf("error streaming upload content: %w", err)
+	}
+
+	return mw.Close()
+}
+
+// wait waits for the body to be written and returns the number of file
+// bytes written and their checksum.
+func (a *uploadAttempt) wait() (int64, string) {
+	<-a.done
+	return a.bytes, hex.EncodeToString(a.sum.Sum(nil))
+}
+
+// progressWriter counts the bytes written to it and logs the progress of an
+// upload every tenth of its size, or every uploadProgressInterval bytes when
+// the size is unknown.
+type progressWriter struct {
+	ctx      context.Context
+	fileName string
+	size     int64
+	count    *int64
+	logged   int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	*p.count += int64(len(b))
+
+	interval := int64(uploadProgressInterval)
+	if p.size > 0 {
+		interval = max(p.size/10, 1)
+	}
+
+	if *p.count-p.logged >= interval {
+		p.logged = *p.count
+		fields := map[string]any{
+			"file_name": p.fileName,
+			"bytes":     *p.count,
+		}
+		if p.size > 0 {
+			fields["percent"] = *p.count * 100 / p.size
+		}
+		tflog.Debug(p.ctx, "Imply API upload progress", fields)
+	}
+
+	return len(b), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes a Content-Disposition parameter value, as
+// mime/multipart does for CreateFormFile.
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
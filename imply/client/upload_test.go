@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receivedUpload is what a multipartServer parsed from an upload.
+type receivedUpload struct {
+	field       string
+	fileName    string
+	contentType string
+	content     []byte
+	fields      map[string]string
+}
+
+// multipartServer parses every multipart upload it receives, after
+// answering the first failures requests with 503.
+type multipartServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	attempts int
+	uploads  []receivedUpload
+}
+
+func newMultipartServer(t *testing.T, failures int) *multipartServer {
+	t.Helper()
+
+	s := &multipartServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.attempts++
+		attempt := s.attempts
+		s.mu.Unlock()
+
+		if attempt <= failures {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var upload receivedUpload
+		for field, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			if err != nil {
+				t.Errorf("Open: %v", err)
+				continue
+			}
+			content, _ := io.ReadAll(file)
+			file.Close()
+
+			upload.field = field
+			upload.fileName = headers[0].Filename
+			upload.contentType = headers[0].Header.Get("Content-Type")
+			upload.content = content
+		}
+
+		upload.fields = map[string]string{}
+		for name, values := range r.MultipartForm.Value {
+			upload.fields[name] = values[0]
+		}
+
+		s.mu.Lock()
+		s.uploads = append(s.uploads, upload)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name": %q, "size": %d}`, upload.fileName, len(upload.content))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *multipartServer) result() (int, []receivedUpload) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.attempts, append([]receivedUpload(nil), s.uploads...)
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadMultipartForm(t *testing.T) {
+	server := newMultipartServer(t, 0)
+	c := newTestClient(t, server.URL)
+
+	content := bytes.Repeat([]byte("timestamp,page\n2024-01-02T15:04:05Z,home\n"), 1000)
+	path := writeTestFile(t, "wikipedia.csv", string(content))
+
+	result, err := c.Upload(context.Background(), http.MethodPost, "/projects/project/files", UploadFile{
+		FieldName:   "upload",
+		Path:        path,
+		ContentType: "text/csv",
+		Fields:      map[string]string{"description": "Wikipedia edits", "format": "csv"},
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+
+	_, uploads := server.result()
+	if len(uploads) != 1 {
+		t.Fatalf("uploads = %d, want 1", len(uploads))
+	}
+	upload := uploads[0]
+
+	if upload.field != "upload" {
+		t.Errorf("field = %q, want %q", upload.field, "upload")
+	}
+	if upload.fileName != "wikipedia.csv" {
+		t.Errorf("file name = %q, want %q", upload.fileName, "wikipedia.csv")
+	}
+	if upload.contentType != "text/csv" {
+		t.Errorf("content type = %q, want %q", upload.contentType, "text/csv")
+	}
+	if !bytes.Equal(upload.content, content) {
+		t.Errorf("content differs: got %d bytes, want %d", len(upload.content), len(content))
+	}
+	if upload.fields["description"] != "Wikipedia edits" || upload.fields["format"] != "csv" {
+		t.Errorf("fields = %v, want description and format", upload.fields)
+	}
+
+	if result.Bytes != int64(len(content)) {
+		t.Errorf("Bytes = %d, want %d", result.Bytes, len(content))
+	}
+	if want := sha256Hex(content); result.SHA256 != want {
+		t.Errorf("SHA256 = %s, want %s", result.SHA256, want)
+	}
+	if result.Response["name"] != "wikipedia.csv" {
+		t.Errorf("Response = %v, want the decoded JSON response", result.Response)
+	}
+}
+
+func TestUploadDefaults(t *testing.T) {
+	server := newMultipartServer(t, 0)
+	c := newTestClient(t, server.URL)
+
+	content := []byte(`{"page": "home"}`)
+	result, err := c.Upload(context.Background(), http.MethodPost, "/projects/project/files", UploadFile{
+		FileName: "events.json",
+		Reader:   bytes.NewReader(content),
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+
+	_, uploads := server.result()
+	if len(uploads) != 1 {
+		t.Fatalf("uploads = %d, want 1", len(uploads))
+	}
+	if uploads[0].field != defaultUploadField {
+		t.Errorf("field = %q, want %q", uploads[0].field, defaultUploadField)
+	}
+	if uploads[0].contentType != defaultUploadContentType {
+		t.Errorf("content type = %q, want %q", uploads[0].contentType, defaultUploadContentType)
+	}
+	if want := sha256Hex(content); result.SHA256 != want || result.Bytes != int64(len(content)) {
+		t.Errorf("result = %d bytes %s, want %d bytes %s", result.Bytes, result.SHA256, len(content), want)
+	}
+}
+
+func TestUploadRetries(t *testing.T) {
+	content := []byte(strings.Repeat("logo", 4096))
+
+	t.Run("path upload is replayed", func(t *testing.T) {
+		server := newMultipartServer(t, 1)
+		c := newTestClient(t, server.URL, WithRetry(2, 10*time.Millisecond))
+
+		result, err := c.Upload(context.Background(), http.MethodPut, "/customizations/logos/light", UploadFile{
+			Path: writeTestFile(t, "logo.svg", string(content)),
+		})
+		if err != nil {
+			t.Fatalf("Upload: %v", err)
+		}
+
+		attempts, uploads := server.result()
+		if attempts != 2 {
+			t.Errorf("attempts = %d, want 2", attempts)
+		}
+		if len(uploads) != 1 || !bytes.Equal(uploads[0].content, content) {
+			t.Fatalf("uploads = %d, want the full file once after the retry", len(uploads))
+		}
+		if want := sha256Hex(content); result.SHA256 != want || result.Bytes != int64(len(content)) {
+			t.Errorf("result = %d bytes %s, want %d bytes %s", result.Bytes, result.SHA256, len(content), want)
+		}
+	})
+
+	t.Run("reader upload is not retried", func(t *testing.T) {
+		server := newMultipartServer(t, 1)
+		c := newTestClient(t, server.URL, WithRetry(2, 10*time.Millisecond))
+
+		_, err := c.Upload(context.Background(), http.MethodPut, "/customizations/logos/light", UploadFile{
+			FileName: "logo.svg",
+			Reader:   bytes.NewReader(content),
+		})
+		if !hasStatus(err, http.StatusServiceUnavailable) {
+			t.Fatalf("Upload error = %v, want the 503 APIError", err)
+		}
+
+		if attempts, _ := server.result(); attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+}
+
+func TestUploadRequiresPathOrReader(t *testing.T) {
+	c := newTestClient(t, "http://localhost")
+
+	tests := map[string]UploadFile{
+		"neither": {},
+		"both":    {Path: "logo.svg", Reader: strings.NewReader("logo")},
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Upload(context.Background(), http.MethodPost, "/files", file); err == nil {
+				t.Fatal("Upload succeeded, want an error")
+			}
+		})
+	}
+}
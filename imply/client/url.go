@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// apiVersionPath is the version prefix every Polaris endpoint lives
+	// under.
+	apiVersionPath = "/v1"
+
+	appDomain = ".app.imply.io"
+	apiDomain = ".api.imply.io"
+
+	// regionalCloudProvider is the only cloud provider Polaris currently
+	// hosts projects on.
+	regionalCloudProvider = "aws"
+)
+
+// normalizeHost turns a user-supplied Imply host into the base URL of its
+// API, without the /v1 prefix. The host may be given with or without a
+// scheme, with a trailing slash or /v1, and as the console address
+// (ORG.app.imply.io), which is rewritten to the API address
+// (ORG.api.imply.io). Custom domains and local stand-ins are kept as they
+// are, including any path prefix.
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	base, err := parseBaseURL(host)
+	if err != nil {
+		return "", err
+	}
+
+	if org, ok := strings.CutSuffix(base.Hostname(), appDomain); ok {
+		host := org + apiDomain
+		if port := base.Port(); port != "" {
+			host += ":" + port
+		}
+		base.Host = host
+	}
+
+	return base.String(), nil
+}
+
+// normalizeAPIURL validates an explicit API URL and returns it without the
+// /v1 prefix. Unlike normalizeHost, the host is never rewritten.
+func normalizeAPIURL(apiURL string) (string, error) {
+	base, err := parseBaseURL(strings.TrimSpace(apiURL))
+	if err != nil {
+		return "", err
+	}
+
+	return base.String(), nil
+}
+
+// parseBaseURL parses and validates an API base URL, cleaning its path and
+// dropping a trailing /v1 so that the version prefix can be appended
+// exactly once.
+func parseBaseURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return nil, fmt.Errorf("invalid URL %q: scheme must be http or https", raw)
+	}
+
+	if parsed.Host == "" || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid URL %q: missing host", raw)
+	}
+
+	if parsed.User != nil {
+		return nil, fmt.Errorf("invalid URL %q: credentials must not be part of the URL", parsed.Redacted())
+	}
+
+	if parsed.RawQuery != "" || parsed.ForceQuery || parsed.Fragment != "" {
+		return nil, fmt.Errorf("invalid URL %q: must not contain a query or fragment", raw)
+	}
+
+	cleaned := ""
+	if parsed.Path != "" && parsed.Path != "/" {
+		cleaned = path.Clean("/" + parsed.Path)
+	}
+	cleaned = strings.TrimSuffix(cleaned, "/")
+	cleaned = strings.TrimSuffix(cleaned, apiVersionPath)
+
+	return &url.URL{
+		Scheme: parsed.Scheme,
+		Host:   strings.ToLower(parsed.Host),
+		Path:   cleaned,
+	}, nil
+}
+
+// regionalURL derives the regional API URL for region from the global one,
+// turning https://ORG.api.imply.io/v1 into
+// https://ORG.REGION.aws.api.imply.io/v1. Hosts outside api.imply.io, such as
+// custom domains and local stand-ins, serve every scope themselves and are
+// returned unchanged, as are hosts that already name a region.
+func regionalURL(hostURL, region string) string {
+	if region == "" {
+		return hostURL
+	}
+
+	parsed, err := url.Parse(hostURL)
+	if err != nil {
+		return hostURL
+	}
+
+	org, ok := strings.CutSuffix(parsed.Hostname(), apiDomain)
+	if !ok || strings.Contains(org, ".") {
+		return hostURL
+	}
+
+	host := org + "." + region + "." + regionalCloudProvider + apiDomain
+	if port := parsed.Port(); port != "" {
+		host += ":" + port
+	}
+	parsed.Host = host
+
+	return parsed.String()
+}
@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+
+package imply
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// defaultProfile is the credentials profile used when none is selected.
+	defaultProfile = "default"
+
+	// noSource is the source of settings that were not set anywhere.
+	noSource = "no source"
+)
+
+// profileKeys are the provider settings a credentials profile may hold.
+var profileKeys = map[string]bool{
+	"api_key":              true,
+	"api_key_command":      true,
+	"api_key_file":         true,
+	"api_url":              true,
+	"auth_method":          true,
+	"ca_cert_file":         true,
+	"client_cert":          true,
+	"client_id":            true,
+	"client_key":           true,
+	"client_secret":        true,
+	"host":                 true,
+	"insecure_skip_verify": true,
+	"project_id":           true,
+	"proxy_url":            true,
+	"region":               true,
+	"token_url":            true,
+}
+
+// credentialsProfile is a named section of a credentials file.
+type credentialsProfile struct {
+	Name   string
+	Path   string
+	Values map[string]string
+}
+
+// defaultCredentialsFile returns the path of ~/.imply/credentials, or an
+// empty string when the home directory is unknown.
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".imply", "credentials")
+}
+
+// loadCredentialsProfile reads the named profile from the credentials file at
+// path. A missing file is only an error when explicit is set, that is when
+// the file or the profile was selected by the practitioner; otherwise no
+// profile is returned.
+func loadCredentialsProfile(path, name string, explicit bool) (*credentialsProfile, error) {
+	if path == "" {
+		if explicit {
+			return nil, fmt.Errorf("profile %q was selected but no credentials file could be located", name)
+		}
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %w", err)
+	}
+
+	profiles, err := parseCredentialsFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing credentials file %s: %w", path, err)
+	}
+
+	values, ok := profiles[name]
+	if !ok {
+		if !explicit {
+			return nil, nil
+		}
+
+		names := make([]string, 0, len(profiles))
+		for profile := range profiles {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("profile %q not found in credentials file %s, available profiles: %s",
+			name, path, strings.Join(names, ", "))
+	}
+
+	return &credentialsProfile{Name: name, Path: path, Values: values}, nil
+}
+
+// parseCredentialsFile parses an INI-style credentials file:
+//
+//	[default]
+//	host    = example.api.imply.io
+//	api_key = pok_...
+//
+//	[prod]
+//	host = example-prod.api.imply.io
+//
+// Lines starting with # or ; are comments, and values may be quoted. A
+// profile or a setting within a profile may only be given once.
+func parseCredentialsFile(data []byte) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated profile header", number)
+			}
+
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", number)
+			}
+			if _, ok := profiles[name]; ok {
+				return nil, fmt.Errorf("line %d: duplicate profile %q", number, name)
+			}
+
+			current = map[string]string{}
+			profiles[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", number)
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a [profile] section", number)
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !profileKeys[key] {
+			return nil, fmt.Errorf("line %d: unsupported setting %q", number, key)
+		}
+
+		if _, ok := current[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate setting %q", number, key)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		current[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// settingSources resolves provider settings from, in order of precedence,
+// the configuration, environment variables and the credentials profile, and
+// records which of them supplied each setting.
+type settingSources struct {
+	profile *credentialsProfile
+	sources map[string]string
+
+	// excluded holds the settings of an exclusive group that are not read
+	// because a higher-precedence source supplies the group.
+	excluded map[string]bool
+}
+
+func newSettingSources(profile *credentialsProfile) *settingSources {
+	return &settingSources{profile: profile, sources: map[string]string{}, excluded: map[string]bool{}}
+}
+
+// exclusiveSetting is a member of a group of mutually exclusive settings.
+type exclusiveSetting struct {
+	Name string
+	// Configured reports whether the provider configuration sets it.
+	Configured bool
+	EnvVar     string
+}
+
+// Exclusive resolves a group of mutually exclusive settings as a unit: the
+// highest-precedence source that sets any of them supplies the whole group,
+// and the members it leaves unset are not read from lower-precedence
+// sources. An api_key_file in the configuration therefore replaces an
+// api_key in the default profile rather than conflicting with it. It must be
+// called before the members are read, and returns the names of the members
+// set by the winning source; more than one is a conflict within that source.
+func (s *settingSources) Exclusive(settings ...exclusiveSetting) []string {
+	levels := []func(exclusiveSetting) bool{
+		func(setting exclusiveSetting) bool { return setting.Configured },
+		func(setting exclusiveSetting) bool { return os.Getenv(setting.EnvVar) != "" },
+		func(setting exclusiveSetting) bool {
+			return s.profile != nil && s.profile.Values[setting.Name] != ""
+		},
+	}
+
+	for _, isSet := range levels {
+		var names []string
+		for _, setting := range settings {
+			if isSet(setting) {
+				names = append(names, setting.Name)
+			}
+		}
+
+		if len(names) == 0 {
+			continue
+		}
+
+		for _, setting := range settings {
+			if !isSet(setting) {
+				s.excluded[setting.Name] = true
+			}
+		}
+		return names
+	}
+
+	return nil
+}
+
+// String resolves the string setting name.
+func (s *settingSources) String(name string, value types.String, envVar string) string {
+	if s.excluded[name] {
+		return ""
+	}
+
+	if !value.IsNull() {
+		s.sources[name] = "the provider configuration"
+		return value.ValueString()
+	}
+
+	if env := os.Getenv(envVar); env != "" {
+		s.sources[name] = "the " + envVar + " environment variable"
+		return env
+	}
+
+	if s.profile != nil {
+		if profileValue, ok := s.profile.Values[name]; ok && profileValue != "" {
+			s.sources[name] = fmt.Sprintf("profile %q in %s", s.profile.Name, s.profile.Path)
+			return profileValue
+		}
+	}
+
+	return ""
+}
+
+// List resolves the list setting name. Outside the configuration, a list is
+// written as a single string of whitespace-separated items.
+func (s *settingSources) List(ctx context.Context, name string, value types.List, envVar string) ([]string, diag.Diagnostics) {
+	if s.excluded[name] {
+		return nil, nil
+	}
+
+	if !value.IsNull() {
+		var items []string
+		diags := value.ElementsAs(ctx, &items, false)
+		s.sources[name] = "the provider configuration"
+		return items, diags
+	}
+
+	return strings.Fields(s.String(name, types.StringNull(), envVar)), nil
+}
+
+// Source describes where the setting name was read from.
+func (s *settingSources) Source(name string) string {
+	if source, ok := s.sources[name]; ok {
+		return source
+	}
+	return noSource
+}
+
+// Summary lists the source of every resolved setting, for diagnostics.
+func (s *settingSources) Summary() string {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, "- "+name+": "+s.sources[name])
+	}
+	return strings.Join(lines, "\n")
+}
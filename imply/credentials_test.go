@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+
+package imply
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSettingSourcesExclusive(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]string
+		env       map[string]string
+		profile   map[string]string
+		wantNames []string
+		wantKey   string
+		wantFile  string
+		wantCmd   []string
+	}{
+		{
+			name:      "configuration replaces the default profile",
+			config:    map[string]string{"api_key_file": "/run/secrets/imply"},
+			profile:   map[string]string{"api_key": "profile-key"},
+			wantNames: []string{"api_key_file"},
+			wantFile:  "/run/secrets/imply",
+		},
+		{
+			name:      "configuration replaces the environment",
+			config:    map[string]string{"api_key_command": "imply-key --profile ci"},
+			env:       map[string]string{"IMPLY_API_KEY": "env-key"},
+			wantNames: []string{"api_key_command"},
+			wantCmd:   []string{"imply-key", "--profile", "ci"},
+		},
+		{
+			name:      "environment replaces the profile",
+			env:       map[string]string{"IMPLY_API_KEY_FILE": "/tmp/key"},
+			profile:   map[string]string{"api_key": "profile-key", "api_key_command": "imply-key"},
+			wantNames: []string{"api_key_file"},
+			wantFile:  "/tmp/key",
+		},
+		{
+			name:      "profile alone",
+			profile:   map[string]string{"api_key": "profile-key"},
+			wantNames: []string{"api_key"},
+			wantKey:   "profile-key",
+		},
+		{
+			name:      "conflict within the configuration",
+			config:    map[string]string{"api_key": "key", "api_key_file": "/tmp/key"},
+			profile:   map[string]string{"api_key_command": "imply-key"},
+			wantNames: []string{"api_key", "api_key_file"},
+			wantKey:   "key",
+			wantFile:  "/tmp/key",
+		},
+		{
+			name:      "conflict within the environment",
+			env:       map[string]string{"IMPLY_API_KEY": "key", "IMPLY_API_KEY_COMMAND": "imply-key"},
+			wantNames: []string{"api_key", "api_key_command"},
+			wantKey:   "key",
+			wantCmd:   []string{"imply-key"},
+		},
+		{
+			name: "nothing set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range []string{"IMPLY_API_KEY", "IMPLY_API_KEY_FILE", "IMPLY_API_KEY_COMMAND"} {
+				t.Setenv(envVar, tt.env[envVar])
+			}
+
+			configString := func(name string) types.String {
+				if value, ok := tt.config[name]; ok {
+					return types.StringValue(value)
+				}
+				return types.StringNull()
+			}
+
+			var profile *credentialsProfile
+			if tt.profile != nil {
+				profile = &credentialsProfile{Name: defaultProfile, Path: "credentials", Values: tt.profile}
+			}
+			sources := newSettingSources(profile)
+
+			names := sources.Exclusive(
+				exclusiveSetting{Name: "api_key", Configured: !configString("api_key").IsNull(), EnvVar: "IMPLY_API_KEY"},
+				exclusiveSetting{Name: "api_key_file", Configured: !configString("api_key_file").IsNull(), EnvVar: "IMPLY_API_KEY_FILE"},
+				exclusiveSetting{Name: "api_key_command", Configured: !configList(tt.config["api_key_command"]).IsNull(), EnvVar: "IMPLY_API_KEY_COMMAND"},
+			)
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("Exclusive = %v, want %v", names, tt.wantNames)
+			}
+
+			if got := sources.String("api_key", configString("api_key"), "IMPLY_API_KEY"); got != tt.wantKey {
+				t.Errorf("api_key = %q, want %q", got, tt.wantKey)
+			}
+			if got := sources.String("api_key_file", configString("api_key_file"), "IMPLY_API_KEY_FILE"); got != tt.wantFile {
+				t.Errorf("api_key_file = %q, want %q", got, tt.wantFile)
+			}
+
+			got, diags := sources.List(context.Background(), "api_key_command", configList(tt.config["api_key_command"]), "IMPLY_API_KEY_COMMAND")
+			if diags.HasError() {
+				t.Fatalf("List: %v", diags)
+			}
+			if !slices.Equal(got, tt.wantCmd) {
+				t.Errorf("api_key_command = %v, want %v", got, tt.wantCmd)
+			}
+		})
+	}
+}
+
+// configList returns the space-separated items of value as a configured
+// list, or a null list when value is empty.
+func configList(value string) types.List {
+	if value == "" {
+		return types.ListNull(types.StringType)
+	}
+
+	items := make([]attr.Value, 0)
+	for _, item := range strings.Fields(value) {
+		items = append(items, types.StringValue(item))
+	}
+	return types.ListValueMust(types.StringType, items)
+}
+
+func TestParseCredentialsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]map[string]string
+		wantErr string
+	}{
+		{
+			name: "profiles",
+			data: `
+[default]
+host    = example.api.imply.io
+api_key = pok_default
+
+[prod]
+HOST = example-prod.api.imply.io
+`,
+			want: map[string]map[string]string{
+				"default": {"host": "example.api.imply.io", "api_key": "pok_default"},
+				"prod":    {"host": "example-prod.api.imply.io"},
+			},
+		},
+		{
+			name: "comments",
+			data: `
+# Development organization
+[default]
+; the key is rotated monthly
+host = example.api.imply.io
+  # indented comment
+`,
+			want: map[string]map[string]string{
+				"default": {"host": "example.api.imply.io"},
+			},
+		},
+		{
+			name: "quoted values",
+			data: `
+[ default ]
+api_key_command = "imply-key --profile ci"
+api_key = 'pok_single'
+proxy_url = "http://proxy:3128
+client_id = ""
+host = a=b
+`,
+			want: map[string]map[string]string{
+				"default": {
+					"api_key_command": "imply-key --profile ci",
+					"api_key":         "pok_single",
+					"proxy_url":       `"http://proxy:3128`,
+					"client_id":       "",
+					"host":            "a=b",
+				},
+			},
+		},
+		{
+			name: "empty profile",
+			data: "[empty]\n",
+			want: map[string]map[string]string{"empty": {}},
+		},
+		{
+			name:    "duplicate setting",
+			data:    "[default]\nhost = one\nHost = two\n",
+			wantErr: `line 3: duplicate setting "host"`,
+		},
+		{
+			name:    "duplicate profile",
+			data:    "[default]\n[default]\n",
+			wantErr: `line 2: duplicate profile "default"`,
+		},
+		{
+			name:    "setting outside a profile",
+			data:    "host = example.api.imply.io\n",
+			wantErr: "line 1: setting outside of a [profile] section",
+		},
+		{
+			name:    "unsupported setting",
+			data:    "[default]\nread_only = true\n",
+			wantErr: `line 2: unsupported setting "read_only"`,
+		},
+		{
+			name:    "unterminated header",
+			data:    "[default\n",
+			wantErr: "line 1: unterminated profile header",
+		},
+		{
+			name:    "empty profile name",
+			data:    "[ ]\n",
+			wantErr: "line 1: empty profile name",
+		},
+		{
+			name:    "line without a value",
+			data:    "[default]\nhost\n",
+			wantErr: "line 2: expected key = value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCredentialsFile([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseCredentialsFile error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCredentialsFile: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("profiles = %v, want %v", got, tt.want)
+			}
+			for name, values := range tt.want {
+				if !maps.Equal(got[name], values) {
+					t.Errorf("profile %q = %v, want %v", name, got[name], values)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(path, []byte("[default]\nhost = example.api.imply.io\n\n[prod]\nhost = example-prod.api.imply.io\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name     string
+		path     string
+		profile  string
+		explicit bool
+		wantHost string
+		wantErr  string
+	}{
+		{name: "default profile", path: path, profile: "default", wantHost: "example.api.imply.io"},
+		{name: "selected profile", path: path, profile: "prod", explicit: true, wantHost: "example-prod.api.imply.io"},
+		{name: "missing default profile is ignored", path: path, profile: "staging"},
+		{name: "missing selected profile", path: path, profile: "staging", explicit: true, wantErr: `profile "staging" not found in credentials file ` + path + `, available profiles: default, prod`},
+		{name: "missing default file is ignored", path: missing, profile: "default"},
+		{name: "missing selected file", path: missing, profile: "default", explicit: true, wantErr: "error reading credentials file"},
+		{name: "no file location", profile: "prod", explicit: true, wantErr: `profile "prod" was selected but no credentials file could be located`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := loadCredentialsProfile(tt.path, tt.profile, tt.explicit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadCredentialsProfile error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadCredentialsProfile: %v", err)
+			}
+
+			if tt.wantHost == "" {
+				if profile != nil {
+					t.Errorf("profile = %+v, want none", profile)
+				}
+				return
+			}
+			if profile == nil || profile.Values["host"] != tt.wantHost || profile.Name != tt.profile || profile.Path != tt.path {
+				t.Errorf("profile = %+v, want %q from %s with host %q", profile, tt.profile, tt.path, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsProfileParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("host = example.api.imply.io\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// A file that cannot be parsed is reported even when nothing selected it.
+	_, err := loadCredentialsProfile(path, defaultProfile, false)
+	if err == nil || !strings.Contains(err.Error(), "error parsing credentials file "+path+": line 1") {
+		t.Fatalf("loadCredentialsProfile error = %v, want a parse error naming the file and line", err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestCheckPreconditionFailed(t *testing.T) {
+	var gotIfMatch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfMatch = r.Header.Get("If-Match")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPreconditionFailed)
+		_, _ = w.Write([]byte(`{"code": "PreconditionFailed", "message": "The group was modified."}`))
+	}))
+	defer server.Close()
+
+	apiKey := "test-key"
+	c, err := client.NewClient(nil, &apiKey, client.WithAPIURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	name := "analysts"
+	_, err = c.API.UpdateGroupWithResponse(context.Background(), "42", polarisapi.GroupRepresentation{Name: &name}, client.IfMatch(`"v1"`))
+	if gotIfMatch != `"v1"` {
+		t.Errorf("If-Match = %q, want %q", gotIfMatch, `"v1"`)
+	}
+
+	var diags diag.Diagnostics
+	if !checkPreconditionFailed(&diags, err, "Imply Group Changed Outside Terraform", "group") {
+		t.Fatalf("checkPreconditionFailed(%v) = false, want true", err)
+	}
+
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("diagnostics = %v, want one error", diags)
+	}
+	d := diags.Errors()[0]
+	if d.Summary() != "Imply Group Changed Outside Terraform" {
+		t.Errorf("summary = %q", d.Summary())
+	}
+	for _, want := range []string{"The group was changed outside Terraform", "terraform plan", "The group was modified."} {
+		if !strings.Contains(d.Detail(), want) {
+			t.Errorf("detail = %q, want it to contain %q", d.Detail(), want)
+		}
+	}
+}
+
+func TestCheckPreconditionFailedOtherErrors(t *testing.T) {
+	for _, err := range []error{
+		nil,
+		errors.New("connection refused"),
+		&client.APIError{StatusCode: http.StatusConflict},
+	} {
+		var diags diag.Diagnostics
+		if checkPreconditionFailed(&diags, err, "Imply Group Changed Outside Terraform", "group") || diags.HasError() {
+			t.Errorf("checkPreconditionFailed(%v) handled the error, want it left to the caller", err)
+		}
+	}
+}
@@ -85,50 +85,28 @@ func (d *groupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// Get group by ID
-	group, err := d.client.Get(fmt.Sprintf("/groups/%s", state.ID.ValueString()))
+	response, err := d.client.API.GetGroupWithResponse(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Imply Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	// Map response body to model
-	state.ID = types.StringValue(fmt.Sprintf("%v", group["id"]))
-	state.Name = types.StringValue(fmt.Sprintf("%v", group["name"]))
-
-	// Handle read_only
-	if readOnly, ok := group["readOnly"].(bool); ok {
-		state.ReadOnly = types.BoolValue(readOnly)
-	}
-
-	// Handle user_count
-	if userCount, ok := group["userCount"].(float64); ok {
-		state.UserCount = types.Int64Value(int64(userCount))
+	group, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Imply Group",
+			err.Error(),
+		)
+		return
 	}
 
-	// Handle permissions
-	if perms, ok := group["permissions"].([]any); ok && len(perms) > 0 {
-		for _, p := range perms {
-			perm, ok := p.(map[string]any)
-			if !ok {
-				continue
-			}
-			permModel := PermissionModel{
-				ID:   types.StringValue(fmt.Sprintf("%v", perm["id"])),
-				Name: types.StringValue(fmt.Sprintf("%v", perm["name"])),
-			}
-
-			// Handle resources
-			if resources, ok := perm["resources"].([]any); ok && len(resources) > 0 {
-				for _, r := range resources {
-					permModel.Resources = append(permModel.Resources, types.StringValue(fmt.Sprintf("%v", r)))
-				}
-				state.Permissions = append(state.Permissions, permModel)
-			}
-		}
-	}
+	// Map response body to model, keeping the configured ID
+	id := state.ID
+	state = apiGroupModel(group)
+	state.ID = id
 
 	// Set state
 	diags := resp.State.Set(ctx, &state)
@@ -5,15 +5,19 @@ package auth
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
@@ -31,16 +35,17 @@ type groupMemberResource struct {
 }
 
 type groupMemberResourceModel struct {
-	ID      types.String `tfsdk:"id"`
-	GroupID types.String `tfsdk:"group_id"`
-	UserID  types.String `tfsdk:"user_id"`
+	ID       types.String   `tfsdk:"id"`
+	GroupID  types.String   `tfsdk:"group_id"`
+	UserID   types.String   `tfsdk:"user_id"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *groupMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_group_member"
 }
 
-func (r *groupMemberResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *groupMemberResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -59,21 +64,37 @@ func (r *groupMemberResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
 func (r *groupMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "create the Imply group member") {
+		return
+	}
+
 	var plan groupMemberResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_, err := r.client.Post(fmt.Sprintf("/groups/%s/members", plan.GroupID.ValueString()), []map[string]any{
-		{"id": plan.UserID.ValueString()},
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	_, err := r.client.API.AddGroupMembersWithResponse(ctx, plan.GroupID.ValueString(), polarisapi.AddGroupMembersJSONRequestBody{
+		{Id: plan.UserID.ValueStringPointer()},
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to Add Imply Group Member", err.Error())
+		resp.Diagnostics.AddError("Unable to Add Imply Group Member", apiErrorDetail(err))
 		return
 	}
 
@@ -88,18 +109,35 @@ func (r *groupMemberResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	members, err := r.client.Get(fmt.Sprintf("/groups/%s/members", state.GroupID.ValueString()))
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	members, err := listGroupMemberIDs(ctx, r.client, state.GroupID.ValueString())
 	if err != nil {
-		if isNotFoundError(err) {
+		if client.IsNotFound(err) {
+			tflog.Warn(ctx, "Imply group no longer exists, removing group member from state", map[string]any{
+				"group_id": state.GroupID.ValueString(),
+				"user_id":  state.UserID.ValueString(),
+			})
 			resp.State.RemoveResource(ctx)
 			return
 		}
 
-		resp.Diagnostics.AddError("Unable to Read Imply Group Members", err.Error())
+		resp.Diagnostics.AddError("Unable to Read Imply Group Members", apiErrorDetail(err))
 		return
 	}
 
-	if !groupMemberExists(members["values"], state.UserID.ValueString()) {
+	if !slices.Contains(members, state.UserID.ValueString()) {
+		tflog.Warn(ctx, "User is no longer a member of the Imply group, removing group member from state", map[string]any{
+			"group_id": state.GroupID.ValueString(),
+			"user_id":  state.UserID.ValueString(),
+		})
 		resp.State.RemoveResource(ctx)
 		return
 	}
@@ -108,21 +146,47 @@ func (r *groupMemberResource) Read(ctx context.Context, req resource.ReadRequest
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func (r *groupMemberResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+// Update only ever sees changes to the timeouts block, as every other
+// attribute requires replacement.
+func (r *groupMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "update the Imply group member") {
+		return
+	}
+
+	var plan groupMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (r *groupMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "delete the Imply group member") {
+		return
+	}
+
 	var state groupMemberResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_, err := r.client.DeleteWithBody(fmt.Sprintf("/groups/%s/members", state.GroupID.ValueString()), []map[string]any{
-		{"id": state.UserID.ValueString()},
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_, err := r.client.API.RemoveGroupMembersWithResponse(ctx, state.GroupID.ValueString(), polarisapi.RemoveGroupMembersJSONRequestBody{
+		{Id: state.UserID.ValueStringPointer()},
 	})
-	if err != nil && !isNotFoundError(err) {
-		resp.Diagnostics.AddError("Unable to Remove Imply Group Member", err.Error())
+	if err != nil && !client.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Remove Imply Group Member", apiErrorDetail(err))
 	}
 }
 
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &groupMembersDataSource{}
+	_ datasource.DataSourceWithConfigure      = &groupMembersDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &groupMembersDataSource{}
+)
+
+func NewGroupMembersDataSource() datasource.DataSource {
+	return &groupMembersDataSource{}
+}
+
+// groupMembersDataSource lists the members of a group, reading every page
+// of /groups/{id}/members.
+type groupMembersDataSource struct {
+	client *client.Client
+}
+
+type groupMembersDataSourceModel struct {
+	GroupID types.String `tfsdk:"group_id"`
+	Limit   types.Int64  `tfsdk:"limit"`
+	Items   []UserModel  `tfsdk:"items"`
+}
+
+func (d *groupMembersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+func (d *groupMembersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the members of an Imply group.",
+		Attributes: map[string]schema.Attribute{
+			"group_id": schema.StringAttribute{
+				Description: "The ID of the group.",
+				Required:    true,
+			},
+			"limit": limitAttribute("members"),
+			"items": userItemsAttribute(),
+		},
+	}
+}
+
+// ValidateConfig rejects a limit that is not positive.
+func (d *groupMembersDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var limit types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("limit"), &limit)...)
+	validateLimit(limit, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *groupMembersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state groupMembersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := listGroupMembers(ctx, d.client, state.GroupID.ValueString(), int(state.Limit.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Imply Group Members",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Items = make([]UserModel, 0, len(members))
+	for i := range members {
+		state.Items = append(state.Items, apiUserModel(&members[i]))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *groupMembersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
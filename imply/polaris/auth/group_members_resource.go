@@ -0,0 +1,348 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// groupMembersBatchSize bounds how many users a single add or remove
+// request carries.
+const groupMembersBatchSize = 100
+
+var (
+	_ resource.Resource                = &groupMembersResource{}
+	_ resource.ResourceWithConfigure   = &groupMembersResource{}
+	_ resource.ResourceWithImportState = &groupMembersResource{}
+)
+
+func NewGroupMembersResource() resource.Resource {
+	return &groupMembersResource{}
+}
+
+// groupMembersResource manages the complete membership of a group: users
+// added outside Terraform are removed on the next apply.
+type groupMembersResource struct {
+	client *client.Client
+}
+
+type groupMembersResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	GroupID  types.String   `tfsdk:"group_id"`
+	UserIDs  types.Set      `tfsdk:"user_ids"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *groupMembersResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+func (r *groupMembersResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the complete membership of an Imply group. The resource is authoritative: " +
+			"users added to the group outside Terraform, for example in the Imply UI, show up as drift " +
+			"and are removed on the next apply.\n\n" +
+			"Do not combine `imply_group_members` with `imply_group_member` resources for the same group, " +
+			"as they will remove each other's members.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_id": schema.StringAttribute{
+				Description: "The ID of the group whose members are managed. Changing it replaces the resource.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_ids": schema.SetAttribute{
+				Description: "The IDs of every user in the group. Set `user_ids = []` to remove all members.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *groupMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "create the Imply group members") {
+		return
+	}
+
+	var plan groupMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if !r.reconcile(ctx, plan, &resp.Diagnostics) {
+		return
+	}
+
+	plan.ID = plan.GroupID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *groupMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state groupMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	members, err := listGroupMemberIDs(ctx, r.client, state.GroupID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			tflog.Warn(ctx, "Imply group no longer exists, removing its members from state", map[string]any{
+				"group_id": state.GroupID.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to Read Imply Group Members", apiErrorDetail(err))
+		return
+	}
+
+	userIDs, diags := types.SetValueFrom(ctx, types.StringType, members)
+	resp.Diagnostics.Append(diags...)
+
+	state.ID = state.GroupID
+	state.UserIDs = userIDs
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *groupMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "update the Imply group members") {
+		return
+	}
+
+	var plan groupMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if !r.reconcile(ctx, plan, &resp.Diagnostics) {
+		return
+	}
+
+	plan.ID = plan.GroupID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the users this resource manages from the group, leaving
+// any added since the last refresh in place.
+func (r *groupMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "delete the Imply group members") {
+		return
+	}
+
+	var state groupMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var userIDs []string
+	resp.Diagnostics.Append(state.UserIDs.ElementsAs(ctx, &userIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := removeGroupMembers(ctx, r.client, state.GroupID.ValueString(), userIDs)
+	if err != nil && !client.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Remove Imply Group Members", apiErrorDetail(err))
+	}
+}
+
+// ImportState imports the membership of a group by its ID.
+func (r *groupMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), req.ID)...)
+}
+
+func (r *groupMembersResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// reconcile makes the group's members match the planned user IDs, adding
+// the missing users and removing every other member.
+func (r *groupMembersResource) reconcile(ctx context.Context, plan groupMembersResourceModel, diags *diag.Diagnostics) bool {
+	var desired []string
+	diags.Append(plan.UserIDs.ElementsAs(ctx, &desired, false)...)
+	if diags.HasError() {
+		return false
+	}
+
+	groupID := plan.GroupID.ValueString()
+
+	current, err := listGroupMemberIDs(ctx, r.client, groupID)
+	if err != nil {
+		diags.AddError("Unable to Read Imply Group Members", apiErrorDetail(err))
+		return false
+	}
+
+	add := setDifference(desired, current)
+	remove := setDifference(current, desired)
+
+	tflog.Debug(ctx, "Reconciling Imply group members", map[string]any{
+		"group_id": groupID,
+		"add":      add,
+		"remove":   remove,
+	})
+
+	if err := addGroupMembers(ctx, r.client, groupID, add); err != nil {
+		diags.AddError("Unable to Add Imply Group Members", apiErrorDetail(err))
+		return false
+	}
+
+	if err := removeGroupMembers(ctx, r.client, groupID, remove); err != nil {
+		diags.AddError("Unable to Remove Imply Group Members", apiErrorDetail(err))
+		return false
+	}
+
+	return true
+}
+
+// listGroupMemberIDs returns the IDs of every member of a group.
+func listGroupMemberIDs(ctx context.Context, c *client.Client, groupID string) ([]string, error) {
+	members, err := listGroupMembers(ctx, c, groupID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.Id != nil && *member.Id != "" {
+			ids = append(ids, *member.Id)
+		}
+	}
+
+	return ids, nil
+}
+
+// addGroupMembers adds users to a group in batches.
+func addGroupMembers(ctx context.Context, c *client.Client, groupID string, userIDs []string) error {
+	for batch := range slices.Chunk(userIDs, groupMembersBatchSize) {
+		if _, err := c.API.AddGroupMembersWithResponse(ctx, groupID, groupMemberUsers(batch)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeGroupMembers removes users from a group in batches.
+func removeGroupMembers(ctx context.Context, c *client.Client, groupID string, userIDs []string) error {
+	for batch := range slices.Chunk(userIDs, groupMembersBatchSize) {
+		if _, err := c.API.RemoveGroupMembersWithResponse(ctx, groupID, groupMemberUsers(batch)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupMemberUsers builds the batch body of the group members endpoint.
+func groupMemberUsers(userIDs []string) []polarisapi.UserRepresentation {
+	users := make([]polarisapi.UserRepresentation, 0, len(userIDs))
+	for _, userID := range userIDs {
+		users = append(users, polarisapi.UserRepresentation{Id: &userID})
+	}
+
+	return users
+}
+
+// setDifference returns the sorted items of a that are not in b.
+func setDifference(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, item := range b {
+		exclude[item] = true
+	}
+
+	var difference []string
+	for _, item := range a {
+		if !exclude[item] {
+			difference = append(difference, item)
+			exclude[item] = true
+		}
+	}
+
+	slices.Sort(difference)
+	return difference
+}
+
+// setIntersection returns the sorted items of a that are also in b.
+func setIntersection(a, b []string) []string {
+	return setDifference(a, setDifference(a, b))
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 
 	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,9 +17,11 @@ import (
 )
 
 var (
-	_ resource.Resource                = &groupResource{}
-	_ resource.ResourceWithConfigure   = &groupResource{}
-	_ resource.ResourceWithImportState = &groupResource{}
+	_ resource.Resource                   = &groupResource{}
+	_ resource.ResourceWithConfigure      = &groupResource{}
+	_ resource.ResourceWithImportState    = &groupResource{}
+	_ resource.ResourceWithValidateConfig = &groupResource{}
+	_ resource.ResourceWithModifyPlan     = &groupResource{}
 )
 
 func NewGroupResource() resource.Resource {
@@ -28,19 +33,25 @@ type groupResource struct {
 }
 
 type groupResourceModel struct {
-	ID          types.String      `tfsdk:"id"`
-	Name        types.String      `tfsdk:"name"`
-	ReadOnly    types.Bool        `tfsdk:"read_only"`
-	Permissions []PermissionModel `tfsdk:"permissions"`
-	UserCount   types.Int64       `tfsdk:"user_count"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	ReadOnly    types.Bool     `tfsdk:"read_only"`
+	Permissions types.Set      `tfsdk:"permissions"`
+	UserCount   types.Int64    `tfsdk:"user_count"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *groupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_group"
 }
 
-func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *groupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages an Imply group and, optionally, the permissions granted to it.\n\n" +
+			"When `permissions` is set, it is authoritative: permissions granted outside Terraform, " +
+			"for example in the Imply UI, show up as drift and are removed on the next apply. " +
+			"Set `permissions = []` to revoke every permission. When `permissions` is left out, " +
+			"the group's permissions are not managed and are only reported.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -51,43 +62,62 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"read_only": schema.BoolAttribute{
 				Computed: true,
 			},
-			"permissions": schema.ListNestedAttribute{
-				Computed: true,
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"id":   schema.StringAttribute{Computed: true},
-						"name": schema.StringAttribute{Computed: true},
-						"resources": schema.ListAttribute{
-							Computed:    true,
-							ElementType: types.StringType,
-						},
-					},
-				},
-			},
+			"permissions": permissionGrantsAttribute("The permissions granted to the group, validated against the permissions returned by `GET /v1/permissions`."),
 			"user_count": schema.Int64Attribute{
 				Computed: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
 func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "create the Imply group") {
+		return
+	}
+
 	var plan groupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	group, err := r.client.Post("/groups", map[string]any{
-		"name": plan.Name.ValueString(),
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	permissions, diags := expandPermissionGrants(ctx, plan.Permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	response, err := r.client.API.CreateGroupWithResponse(ctx, polarisapi.GroupRepresentation{
+		Name:        plan.Name.ValueStringPointer(),
+		Permissions: permissions,
 	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Imply Group", apiErrorDetail(err))
+		return
+	}
+
+	group, err := apiResult(response.JSON201, response.Body)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to Create Imply Group", err.Error())
 		return
 	}
 
-	state := flattenGroupResource(plan, group)
+	state, diags := flattenGroupResource(plan, group)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, client.ETag(response.HTTPResponse))...)
 }
 
 func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -97,22 +127,43 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	group, err := r.client.Get(fmt.Sprintf("/groups/%s", state.ID.ValueString()))
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	response, err := r.client.API.GetGroupWithResponse(ctx, state.ID.ValueString())
 	if err != nil {
-		if isNotFoundError(err) {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
 
+		resp.Diagnostics.AddError("Unable to Read Imply Group", apiErrorDetail(err))
+		return
+	}
+
+	group, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
 		resp.Diagnostics.AddError("Unable to Read Imply Group", err.Error())
 		return
 	}
 
-	state = flattenGroupResource(state, group)
+	state, diags = flattenGroupResource(state, group)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, client.ETag(response.HTTPResponse))...)
 }
 
 func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "update the Imply group") {
+		return
+	}
+
 	var plan groupResourceModel
 	var state groupResourceModel
 
@@ -122,30 +173,117 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	group, err := r.client.Put(fmt.Sprintf("/groups/%s", state.ID.ValueString()), map[string]any{
-		"name": plan.Name.ValueString(),
-	})
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	etag, diags := privateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Permissions left out of the configuration are not managed, even
+	// though the plan carries the current ones over from state.
+	var configured types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &configured)...)
+	permissions, diags := expandPermissionGrants(ctx, configured)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	response, err := r.client.API.UpdateGroupWithResponse(ctx, state.ID.ValueString(), polarisapi.GroupRepresentation{
+		Name:        plan.Name.ValueStringPointer(),
+		Permissions: permissions,
+	}, client.IfMatch(etag))
+	if err != nil {
+		if checkPreconditionFailed(&resp.Diagnostics, err, "Imply Group Changed Outside Terraform", "group") {
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to Update Imply Group", apiErrorDetail(err))
+		return
+	}
+
+	group, err := apiResult(response.JSON200, response.Body)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to Update Imply Group", err.Error())
 		return
 	}
 
-	nextState := flattenGroupResource(plan, group)
+	nextState, diags := flattenGroupResource(plan, group)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &nextState)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, client.ETag(response.HTTPResponse))...)
 }
 
 func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "delete the Imply group") {
+		return
+	}
+
 	var state groupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := r.client.Delete(fmt.Sprintf("/groups/%s", state.ID.ValueString())); err != nil && !isNotFoundError(err) {
-		resp.Diagnostics.AddError("Unable to Delete Imply Group", err.Error())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	etag, diags := privateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_, err := r.client.API.DeleteGroupWithResponse(ctx, state.ID.ValueString(), client.IfMatch(etag))
+	if err != nil && !client.IsNotFound(err) {
+		if checkPreconditionFailed(&resp.Diagnostics, err, "Imply Group Changed Outside Terraform", "group") {
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to Delete Imply Group", apiErrorDetail(err))
 	}
 }
 
+func (r *groupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var permissions types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &permissions)...)
+	resp.Diagnostics.Append(validatePermissionGrants(ctx, path.Root("permissions"), permissions)...)
+}
+
+// ModifyPlan checks changed permission names against the Imply permission
+// catalog, so that a typo fails the plan rather than the apply.
+func (r *groupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned, prior types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("permissions"), &planned)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("permissions"), &prior)...)
+	}
+	if resp.Diagnostics.HasError() || planned.Equal(prior) {
+		return
+	}
+
+	resp.Diagnostics.Append(checkPermissionNames(ctx, r.client, path.Root("permissions"), planned)...)
+}
+
 func (r *groupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -167,12 +305,14 @@ func (r *groupResource) Configure(_ context.Context, req resource.ConfigureReque
 	r.client = client
 }
 
-func flattenGroupResource(plan groupResourceModel, group map[string]any) groupResourceModel {
+func flattenGroupResource(plan groupResourceModel, group *polarisapi.GroupRepresentation) (groupResourceModel, diag.Diagnostics) {
 	state := plan
-	state.ID = stringValue(group, "id")
-	state.Name = stringValue(group, "name")
-	state.ReadOnly = boolValue(group, "readOnly")
-	state.Permissions = permissionModels(group["permissions"])
-	state.UserCount = int64Value(group, "userCount")
-	return state
+	state.ID = stringPointerValue(group.Id)
+	state.Name = stringPointerValue(group.Name)
+	state.ReadOnly = types.BoolPointerValue(group.ReadOnly)
+	state.UserCount = intPointerValue(group.UserCount)
+
+	permissions, diags := flattenPermissionGrants(group.Permissions)
+	state.Permissions = permissions
+	return state, diags
 }
@@ -86,75 +86,26 @@ func (d *groupsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 func (d *groupsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state GroupsModel
 
-	response, err := d.client.Get("/groups")
+	response, err := d.client.API.ListGroupsWithResponse(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Imply Groups",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	// Try to get groups from the response
-	groups, ok := response["values"].([]interface{})
-	if !ok {
+	groups, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Invalid Response Format",
-			fmt.Sprintf("Expected []interface{} in values field, got: %T", response["values"]),
+			"Unable to Read Imply Groups",
+			err.Error(),
 		)
 		return
 	}
 
 	// Map response body to model
-	for _, rawGroup := range groups {
-		group, ok := rawGroup.(map[string]interface{})
-		if !ok {
-			resp.Diagnostics.AddError(
-				"Invalid Group Data",
-				fmt.Sprintf("Expected map[string]interface{}, got: %T", rawGroup),
-			)
-			return
-		}
-
-		groupState := GroupModel{
-			ID:   types.StringValue(fmt.Sprintf("%v", group["id"])),
-			Name: types.StringValue(fmt.Sprintf("%v", group["name"])),
-		}
-
-		// Handle read_only
-		if readOnly, ok := group["readOnly"].(bool); ok {
-			groupState.ReadOnly = types.BoolValue(readOnly)
-		}
-
-		// Handle user_count
-		if userCount, ok := group["userCount"].(float64); ok {
-			groupState.UserCount = types.Int64Value(int64(userCount))
-		}
-
-		// Handle permissions
-		if perms, ok := group["permissions"].([]interface{}); ok && len(perms) > 0 {
-			for _, p := range perms {
-				perm, ok := p.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				permModel := PermissionModel{
-					ID:   types.StringValue(fmt.Sprintf("%v", perm["id"])),
-					Name: types.StringValue(fmt.Sprintf("%v", perm["name"])),
-				}
-
-				// Handle resources
-				if resources, ok := perm["resources"].([]interface{}); ok && len(resources) > 0 {
-					for _, r := range resources {
-						permModel.Resources = append(permModel.Resources, types.StringValue(fmt.Sprintf("%v", r)))
-					}
-					groupState.Permissions = append(groupState.Permissions, permModel)
-				}
-			}
-		}
-
-		state.Items = append(state.Items, groupState)
-	}
+	state.Items = apiGroupModels(&groups.Values)
 
 	// Set state
 	diags := resp.State.Set(ctx, &state)
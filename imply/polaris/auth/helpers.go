@@ -3,70 +3,94 @@
 package auth
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"net/url"
+	"time"
 
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func isNotFoundError(err error) bool {
-	return err != nil && strings.Contains(err.Error(), "status: 404")
-}
+// privateETagKey is the private state key holding the entity tag Polaris
+// last returned for a resource's object.
+const privateETagKey = "etag"
 
-func stringValue(data map[string]any, key string) types.String {
-	value, ok := data[key]
-	if !ok || value == nil {
-		return types.StringNull()
+// Default operation timeouts used when a resource's timeouts block does not
+// set one. Each bounds the whole operation, including retries.
+const (
+	defaultCreateTimeout = 10 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 10 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute
+)
+
+// apiResult returns the typed payload of a generated client response. The
+// generated parsers only decode the documented success status, so the raw
+// body is decoded when the API answers with another 2xx status.
+func apiResult[T any](typed *T, body []byte) (*T, error) {
+	if typed != nil {
+		return typed, nil
 	}
 
-	str := fmt.Sprintf("%v", value)
-	if str == "" || str == "<nil>" {
-		return types.StringNull()
+	if len(body) == 0 {
+		return nil, errors.New("the Imply API returned an empty response")
 	}
 
-	return types.StringValue(str)
+	var result T
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	return &result, nil
 }
 
-func boolValue(data map[string]any, key string) types.Bool {
-	value, ok := data[key].(bool)
-	if !ok {
-		return types.BoolNull()
+func stringPointerValue(value *string) types.String {
+	if value == nil || *value == "" {
+		return types.StringNull()
 	}
 
-	return types.BoolValue(value)
+	return types.StringValue(*value)
 }
 
-func int64Value(data map[string]any, key string) types.Int64 {
-	value, ok := data[key].(float64)
-	if !ok {
+func intPointerValue(value *int) types.Int64 {
+	if value == nil {
 		return types.Int64Null()
 	}
 
-	return types.Int64Value(int64(value))
+	return types.Int64Value(int64(*value))
 }
 
-func permissionModels(raw any) []PermissionModel {
-	values, ok := raw.([]any)
-	if !ok || len(values) == 0 {
-		return []PermissionModel{}
+func timePointerValue(value *time.Time) types.String {
+	if value == nil || value.IsZero() {
+		return types.StringNull()
 	}
 
-	items := make([]PermissionModel, 0, len(values))
-	for _, value := range values {
-		permission, ok := value.(map[string]any)
-		if !ok {
-			continue
-		}
+	return types.StringValue(value.Format(time.RFC3339))
+}
+
+func apiPermissionModels(permissions *[]polarisapi.PermissionRepresentation) []PermissionModel {
+	if permissions == nil || len(*permissions) == 0 {
+		return []PermissionModel{}
+	}
 
+	items := make([]PermissionModel, 0, len(*permissions))
+	for _, permission := range *permissions {
 		item := PermissionModel{
-			ID:   stringValue(permission, "id"),
-			Name: stringValue(permission, "name"),
+			ID:   stringPointerValue(permission.Id),
+			Name: stringPointerValue(permission.Name),
 		}
 
-		if resources, ok := permission["resources"].([]any); ok {
-			item.Resources = make([]types.String, 0, len(resources))
-			for _, resource := range resources {
-				item.Resources = append(item.Resources, types.StringValue(fmt.Sprintf("%v", resource)))
+		if permission.Resources != nil {
+			item.Resources = make([]types.String, 0, len(*permission.Resources))
+			for _, resource := range *permission.Resources {
+				item.Resources = append(item.Resources, types.StringValue(resource))
 			}
 		}
 
@@ -76,70 +100,226 @@ func permissionModels(raw any) []PermissionModel {
 	return items
 }
 
-func groupModels(raw any) []GroupModel {
-	values, ok := raw.([]any)
-	if !ok || len(values) == 0 {
-		return []GroupModel{}
+func apiGroupModel(group *polarisapi.GroupRepresentation) GroupModel {
+	return GroupModel{
+		ID:          stringPointerValue(group.Id),
+		Name:        stringPointerValue(group.Name),
+		ReadOnly:    types.BoolPointerValue(group.ReadOnly),
+		Permissions: apiPermissionModels(group.Permissions),
+		UserCount:   intPointerValue(group.UserCount),
 	}
+}
 
-	items := make([]GroupModel, 0, len(values))
-	for _, value := range values {
-		group, ok := value.(map[string]any)
-		if !ok {
-			continue
-		}
+func apiGroupModels(groups *[]polarisapi.GroupRepresentation) []GroupModel {
+	if groups == nil || len(*groups) == 0 {
+		return []GroupModel{}
+	}
 
-		items = append(items, GroupModel{
-			ID:          stringValue(group, "id"),
-			Name:        stringValue(group, "name"),
-			ReadOnly:    boolValue(group, "readOnly"),
-			Permissions: permissionModels(group["permissions"]),
-			UserCount:   int64Value(group, "userCount"),
-		})
+	items := make([]GroupModel, 0, len(*groups))
+	for i := range *groups {
+		items = append(items, apiGroupModel(&(*groups)[i]))
 	}
 
 	return items
 }
 
-func stringModels(raw any, key string) []types.String {
-	values, ok := raw.([]any)
-	if !ok || len(values) == 0 {
+func apiUserModel(user *polarisapi.UserRepresentation) UserModel {
+	return UserModel{
+		ID:            stringPointerValue(user.Id),
+		Username:      stringPointerValue(user.Username),
+		Email:         stringPointerValue(user.Email),
+		FirstName:     stringPointerValue(user.FirstName),
+		LastName:      stringPointerValue(user.LastName),
+		Enabled:       types.BoolPointerValue(user.Enabled),
+		EmailVerified: types.BoolPointerValue(user.EmailVerified),
+		Permissions:   apiPermissionModels(user.Permissions),
+		Groups:        apiGroupModels(user.Groups),
+		Identities:    apiIdentityModels(user.Identities),
+		Actions:       apiActionModels(user.Actions),
+		CreatedOn:     timePointerValue(user.CreatedOn),
+	}
+}
+
+// apiIdentityModels lists a user's external identities by the ID of their
+// identity provider.
+func apiIdentityModels(identities *[]polarisapi.IdentityRepresentation) []types.String {
+	if identities == nil || len(*identities) == 0 {
 		return []types.String{}
 	}
 
-	items := make([]types.String, 0, len(values))
-	for _, value := range values {
-		switch typed := value.(type) {
-		case string:
-			items = append(items, types.StringValue(typed))
-		case map[string]any:
-			if providerID, ok := typed[key].(string); ok && providerID != "" {
-				items = append(items, types.StringValue(providerID))
-			}
-		default:
-			items = append(items, types.StringValue(fmt.Sprintf("%v", value)))
+	items := make([]types.String, 0, len(*identities))
+	for _, identity := range *identities {
+		if identity.ProviderId != nil {
+			items = append(items, types.StringValue(*identity.ProviderId))
 		}
 	}
 
 	return items
 }
 
-func groupMemberExists(raw any, userID string) bool {
-	values, ok := raw.([]any)
-	if !ok {
+func apiActionModels(actions *[]any) []types.String {
+	if actions == nil || len(*actions) == 0 {
+		return []types.String{}
+	}
+
+	items := make([]types.String, 0, len(*actions))
+	for _, action := range *actions {
+		items = append(items, types.StringValue(fmt.Sprintf("%v", action)))
+	}
+
+	return items
+}
+
+// apiItems decodes the items of a list read through client.Paginate into
+// their generated representation.
+func apiItems[T any](items []map[string]any) ([]T, error) {
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding list items: %w", err)
+	}
+
+	var result []T
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling list items: %w", err)
+	}
+
+	return result, nil
+}
+
+// listUsers returns every user matching search, or every user when search
+// is empty. A positive limit stops reading once that many users are read.
+func listUsers(ctx context.Context, c *client.Client, search string, limit int) ([]polarisapi.UserRepresentation, error) {
+	query := url.Values{}
+	if search != "" {
+		query.Set("search", search)
+	}
+
+	items, err := c.Paginate(ctx, "/users", query, client.PaginationTopSkip, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiItems[polarisapi.UserRepresentation](items)
+}
+
+// listGroupMembers returns every member of a group. A positive limit stops
+// reading once that many members are read.
+func listGroupMembers(ctx context.Context, c *client.Client, groupID string, limit int) ([]polarisapi.UserRepresentation, error) {
+	items, err := c.Paginate(ctx, "/groups/"+url.PathEscape(groupID)+"/members", nil, client.PaginationTopSkip, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiItems[polarisapi.UserRepresentation](items)
+}
+
+// limitAttribute returns the schema of the optional limit of a data source
+// listing things, such as users.
+func limitAttribute(things string) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Description: "The maximum number of " + things + " to read. Leave unset to read them all.",
+		Optional:    true,
+	}
+}
+
+// validateLimit adds an error diagnostic when a configured limit is not
+// positive.
+func validateLimit(limit types.Int64, diags *diag.Diagnostics) {
+	if !limit.IsNull() && !limit.IsUnknown() && limit.ValueInt64() < 1 {
+		diags.AddAttributeError(
+			path.Root("limit"),
+			"Invalid Limit",
+			fmt.Sprintf("limit must be at least 1, got: %d. Leave it unset to read every item.", limit.ValueInt64()),
+		)
+	}
+}
+
+// apiErrorDetail renders a client error for a diagnostic detail, adding
+// guidance for the API errors practitioners can act on.
+func apiErrorDetail(err error) string {
+	switch {
+	case client.IsForbidden(err):
+		return err.Error() + "\n\nThe configured credentials are not permitted to perform this operation."
+	case client.IsConflict(err):
+		return err.Error() + "\n\nThe object already exists or conflicts with an existing object. Consider importing it instead."
+	default:
+		return err.Error()
+	}
+}
+
+// checkWritable reports whether changes may be made, adding an error
+// diagnostic naming the refused operation when the provider is read-only.
+// Resources call it before anything else in Create, Update and Delete.
+func checkWritable(c *client.Client, diags *diag.Diagnostics, operation string) bool {
+	if err := c.CheckWritable(); err != nil {
+		diags.AddError(
+			"Provider Is Read-Only",
+			"Refusing to "+operation+" because the provider is configured with read_only (or IMPLY_READ_ONLY) enabled, "+
+				"which blocks every change. Disable read_only to apply changes.",
+		)
 		return false
 	}
+	return true
+}
 
-	for _, value := range values {
-		member, ok := value.(map[string]any)
-		if !ok {
-			continue
-		}
+// privateStateGetter and privateStateSetter are the parts of a resource's
+// private state used to keep entity tags and other values Terraform does not
+// show.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
 
-		if fmt.Sprintf("%v", member["id"]) == userID {
-			return true
-		}
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// privateETag returns the entity tag stored in private state, or an empty
+// string when there is none.
+func privateETag(ctx context.Context, private privateStateGetter) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateETagKey)
+	if diags.HasError() || len(value) == 0 {
+		return "", diags
+	}
+
+	var etag string
+	if err := json.Unmarshal(value, &etag); err != nil {
+		diags.AddError("Invalid Private State", "Unable to decode the stored entity tag: "+err.Error())
+		return "", diags
+	}
+
+	return etag, diags
+}
+
+// setPrivateETag stores etag in private state, removing any previous tag
+// when the response carried none.
+func setPrivateETag(ctx context.Context, private privateStateSetter, etag string) diag.Diagnostics {
+	if etag == "" {
+		return private.SetKey(ctx, privateETagKey, nil)
+	}
+
+	value, err := json.Marshal(etag)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Invalid Private State", "Unable to encode the entity tag: "+err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, privateETagKey, value)
+}
+
+// checkPreconditionFailed reports whether err is a 412 response to a request
+// sent with If-Match, adding an error diagnostic explaining that object was
+// changed outside Terraform since it was last read.
+func checkPreconditionFailed(diags *diag.Diagnostics, err error, summary, object string) bool {
+	if !client.IsPreconditionFailed(err) {
+		return false
 	}
 
-	return false
+	diags.AddError(
+		summary,
+		"The "+object+" was changed outside Terraform after it was last read, so the change was not applied "+
+			"to avoid overwriting it. Run terraform plan or terraform apply again to refresh the "+object+
+			" and review an up-to-date plan.\n\n"+err.Error(),
+	)
+	return true
 }
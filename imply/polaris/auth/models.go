@@ -44,5 +44,6 @@ type UserModel struct {
 }
 
 type UsersModel struct {
+	Limit types.Int64 `tfsdk:"limit"`
 	Items []UserModel `tfsdk:"items"`
 }
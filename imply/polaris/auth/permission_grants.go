@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// permissionGrantModel is a permission granted by name, optionally scoped
+// to resources such as "projects/*". Unlike PermissionModel it holds only
+// what a practitioner configures, so that grants compare equal as set
+// elements.
+type permissionGrantModel struct {
+	Name      types.String `tfsdk:"name"`
+	Resources types.Set    `tfsdk:"resources"`
+}
+
+// permissionGrantAttrTypes are the attribute types of permissionGrantModel.
+var permissionGrantAttrTypes = map[string]attr.Type{
+	"name":      types.StringType,
+	"resources": types.SetType{ElemType: types.StringType},
+}
+
+// permissionGrantsAttribute returns the schema of a configurable set of
+// permission grants with the given description. When it is left out of the
+// configuration the grants are not managed, and the current ones are kept in
+// state.
+func permissionGrantsAttribute(description string) schema.SetNestedAttribute {
+	return schema.SetNestedAttribute{
+		Description: description,
+		Optional:    true,
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Description: "The permission name, such as `ManageDataCubes`.",
+					Required:    true,
+				},
+				"resources": schema.SetAttribute{
+					Description: "The resources the permission is scoped to, such as `projects/*`. Leave unset to grant the permission unscoped.",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+			},
+		},
+		PlanModifiers: []planmodifier.Set{
+			setplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// flattenPermissionGrants converts API permissions to a set of grants.
+// Unscoped permissions have null resources.
+func flattenPermissionGrants(permissions *[]polarisapi.PermissionRepresentation) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elementType := types.ObjectType{AttrTypes: permissionGrantAttrTypes}
+
+	if permissions == nil {
+		return types.SetValueMust(elementType, []attr.Value{}), diags
+	}
+
+	elements := make([]attr.Value, 0, len(*permissions))
+	for _, permission := range *permissions {
+		resources := types.SetNull(types.StringType)
+		if permission.Resources != nil && len(*permission.Resources) > 0 {
+			values := make([]attr.Value, 0, len(*permission.Resources))
+			for _, resource := range *permission.Resources {
+				values = append(values, types.StringValue(resource))
+			}
+
+			var d diag.Diagnostics
+			resources, d = types.SetValue(types.StringType, values)
+			diags.Append(d...)
+		}
+
+		element, d := types.ObjectValue(permissionGrantAttrTypes, map[string]attr.Value{
+			"name":      stringPointerValue(permission.Name),
+			"resources": resources,
+		})
+		diags.Append(d...)
+		elements = append(elements, element)
+	}
+
+	set, d := types.SetValue(elementType, elements)
+	diags.Append(d...)
+	return set, diags
+}
+
+// expandPermissionGrants converts a set of grants to API permissions. It
+// returns nil when the set is null or unknown, that is when the grants are
+// not managed.
+func expandPermissionGrants(ctx context.Context, grants types.Set) (*[]polarisapi.PermissionRepresentation, diag.Diagnostics) {
+	if grants.IsNull() || grants.IsUnknown() {
+		return nil, nil
+	}
+
+	var models []permissionGrantModel
+	diags := grants.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	permissions := make([]polarisapi.PermissionRepresentation, 0, len(models))
+	for _, model := range models {
+		permission := polarisapi.PermissionRepresentation{
+			Name: model.Name.ValueStringPointer(),
+		}
+
+		if !model.Resources.IsNull() && !model.Resources.IsUnknown() {
+			var resources []string
+			diags.Append(model.Resources.ElementsAs(ctx, &resources, false)...)
+			sort.Strings(resources)
+			permission.Resources = &resources
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		return *permissions[i].Name < *permissions[j].Name
+	})
+
+	return &permissions, diags
+}
+
+// validatePermissionGrants checks the configured grants for mistakes that
+// need no API call: a permission granted twice, an empty scope list and
+// blank resources. Unknown values are skipped.
+func validatePermissionGrants(ctx context.Context, attribute path.Path, grants types.Set) diag.Diagnostics {
+	if grants.IsNull() || grants.IsUnknown() {
+		return nil
+	}
+
+	var models []permissionGrantModel
+	diags := grants.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return diags
+	}
+
+	seen := map[string]bool{}
+	for _, model := range models {
+		if model.Name.IsUnknown() {
+			continue
+		}
+
+		name := model.Name.ValueString()
+		if seen[name] {
+			diags.AddAttributeError(attribute, "Duplicate Imply Permission",
+				"The permission "+name+" is granted more than once. Grant each permission once, "+
+					"listing every resource it applies to in its resources.")
+		}
+		seen[name] = true
+
+		if model.Resources.IsNull() || model.Resources.IsUnknown() {
+			continue
+		}
+
+		if len(model.Resources.Elements()) == 0 {
+			diags.AddAttributeError(attribute, "Empty Imply Permission Scope",
+				"The permission "+name+" has an empty resources set. Leave resources out to grant the permission unscoped.")
+			continue
+		}
+
+		var resources []types.String
+		diags.Append(model.Resources.ElementsAs(ctx, &resources, false)...)
+		for _, resource := range resources {
+			if !resource.IsUnknown() && strings.TrimSpace(resource.ValueString()) == "" {
+				diags.AddAttributeError(attribute, "Invalid Imply Permission Scope",
+					"The permission "+name+" is scoped to a blank resource.")
+			}
+		}
+	}
+
+	return diags
+}
+
+// checkPermissionNames verifies that every granted permission exists in the
+// catalog returned by GET /v1/permissions. When the catalog cannot be read,
+// a warning is added and the API is left to reject unknown names.
+func checkPermissionNames(ctx context.Context, c *client.Client, attribute path.Path, grants types.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if grants.IsNull() || grants.IsUnknown() {
+		return diags
+	}
+
+	var models []permissionGrantModel
+	diags.Append(grants.ElementsAs(ctx, &models, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	response, err := c.API.ListPermissionsWithResponse(ctx)
+	if err != nil {
+		diags.AddAttributeWarning(attribute, "Unable to Validate Imply Permissions",
+			"The permission names could not be checked against the Imply permission catalog: "+apiErrorDetail(err))
+		return diags
+	}
+
+	catalog, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
+		diags.AddAttributeWarning(attribute, "Unable to Validate Imply Permissions",
+			"The permission names could not be checked against the Imply permission catalog: "+err.Error())
+		return diags
+	}
+
+	known := map[string]bool{}
+	for _, permission := range catalog.Values {
+		if permission.Name != nil {
+			known[*permission.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, model := range models {
+		if model.Name.IsUnknown() || known[model.Name.ValueString()] {
+			continue
+		}
+
+		diags.AddAttributeError(attribute, "Unknown Imply Permission",
+			"The permission "+model.Name.ValueString()+" does not exist. Valid permissions are: "+strings.Join(names, ", ")+".")
+	}
+
+	return diags
+}
@@ -62,50 +62,26 @@ func (d *permissionsDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 func (d *permissionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state PermissionsModel
 
-	response, err := d.client.Get("/permissions")
+	response, err := d.client.API.ListPermissionsWithResponse(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Imply Permissions",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	// Try to get permissions from the response
-	permissions, ok := response["values"].([]interface{})
-	if !ok {
+	permissions, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Invalid Response Format",
-			fmt.Sprintf("Expected []interface{} in values field, got: %T", response["values"]),
+			"Unable to Read Imply Permissions",
+			err.Error(),
 		)
 		return
 	}
 
 	// Map response body to model
-	for _, rawPermission := range permissions {
-		permission, ok := rawPermission.(map[string]interface{})
-		if !ok {
-			resp.Diagnostics.AddError(
-				"Invalid Permission Data",
-				fmt.Sprintf("Expected map[string]interface{}, got: %T", rawPermission),
-			)
-			return
-		}
-
-		permissionState := PermissionModel{
-			ID:   types.StringValue(fmt.Sprintf("%v", permission["id"])),
-			Name: types.StringValue(fmt.Sprintf("%v", permission["name"])),
-		}
-
-		// Handle resources
-		if resources, ok := permission["resources"].([]interface{}); ok && len(resources) > 0 {
-			for _, r := range resources {
-				permissionState.Resources = append(permissionState.Resources, types.StringValue(fmt.Sprintf("%v", r)))
-			}
-		}
-
-		state.Items = append(state.Items, permissionState)
-	}
+	state.Items = apiPermissionModels(&permissions.Values)
 
 	// Set state
 	diags := resp.State.Set(ctx, &state)
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestReadOnlyResourcesFailBeforeAnyRequest(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	apiKey := "test-key"
+	c, err := client.NewClient(nil, &apiKey, client.WithAPIURL(server.URL), client.WithReadOnly(true))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := context.Background()
+	resources := map[string]func() resource.Resource{
+		"imply_user":                   NewUserResource,
+		"imply_group":                  NewGroupResource,
+		"imply_group_member":           NewGroupMemberResource,
+		"imply_group_members":          NewGroupMembersResource,
+		"imply_user_group_memberships": NewUserGroupMembershipsResource,
+	}
+
+	for name, newResource := range resources {
+		t.Run(name, func(t *testing.T) {
+			r := newResource()
+			r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: c}, &resource.ConfigureResponse{})
+
+			// The requests are empty: a read-only resource must fail before
+			// it even reads its plan or state.
+			var createResp resource.CreateResponse
+			r.Create(ctx, resource.CreateRequest{}, &createResp)
+
+			var updateResp resource.UpdateResponse
+			r.Update(ctx, resource.UpdateRequest{}, &updateResp)
+
+			var deleteResp resource.DeleteResponse
+			r.Delete(ctx, resource.DeleteRequest{}, &deleteResp)
+
+			for operation, diags := range map[string][]string{
+				"Create": summaries(createResp.Diagnostics.Errors()),
+				"Update": summaries(updateResp.Diagnostics.Errors()),
+				"Delete": summaries(deleteResp.Diagnostics.Errors()),
+			} {
+				if len(diags) != 1 || diags[0] != "Provider Is Read-Only" {
+					t.Errorf("%s errors = %q, want only the read-only error", operation, diags)
+				}
+			}
+		})
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Errorf("requests = %d, want none to reach the server", got)
+	}
+}
+
+// summaries returns the summary of each diagnostic.
+func summaries(diags diag.Diagnostics) []string {
+	result := make([]string, 0, len(diags))
+	for _, d := range diags {
+		result = append(result, d.Summary())
+	}
+	return result
+}
@@ -0,0 +1,388 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Sources of an effective permission.
+const (
+	permissionSourceDirect  = "direct"
+	permissionSourceGroup   = "group"
+	permissionSourceUnknown = "unknown"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &userEffectivePermissionsDataSource{}
+	_ datasource.DataSourceWithConfigure      = &userEffectivePermissionsDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &userEffectivePermissionsDataSource{}
+)
+
+func NewUserEffectivePermissionsDataSource() datasource.DataSource {
+	return &userEffectivePermissionsDataSource{}
+}
+
+// userEffectivePermissionsDataSource resolves every permission a user holds,
+// directly or through group membership, into permission and resource pairs.
+type userEffectivePermissionsDataSource struct {
+	client *client.Client
+}
+
+type userEffectivePermissionsModel struct {
+	ID              types.String               `tfsdk:"id"`
+	Username        types.String               `tfsdk:"username"`
+	Email           types.String               `tfsdk:"email"`
+	Permissions     []effectivePermissionModel `tfsdk:"permissions"`
+	PermissionNames []types.String             `tfsdk:"permission_names"`
+}
+
+// effectivePermissionModel is a permission held on one resource, or
+// unscoped when Resource is null, along with where it comes from.
+type effectivePermissionModel struct {
+	Name      types.String `tfsdk:"name"`
+	Resource  types.String `tfsdk:"resource"`
+	Source    types.String `tfsdk:"source"`
+	GroupID   types.String `tfsdk:"group_id"`
+	GroupName types.String `tfsdk:"group_name"`
+}
+
+func (d *userEffectivePermissionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_effective_permissions"
+}
+
+func (d *userEffectivePermissionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Resolves every permission a user holds, granted directly or through one of their groups, " +
+			"into permission and resource pairs. Look the user up by exactly one of `id`, `username` or `email`.\n\n" +
+			"A pair held both directly and through a group, or through several groups, is listed once per source. " +
+			"Pairs reported by `GET /v1/users/{id}/effectivepermissions` that cannot be traced to a direct " +
+			"permission or a group are listed with source `unknown`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the user.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"username": schema.StringAttribute{
+				Description: "The username of the user, compared case-insensitively.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"email": schema.StringAttribute{
+				Description: "The email of the user, compared case-insensitively.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"permissions": schema.ListNestedAttribute{
+				Description: "The effective permissions, one per permission, resource and source.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The permission name.",
+							Computed:    true,
+						},
+						"resource": schema.StringAttribute{
+							Description: "The resource the permission is scoped to, or null when it is unscoped.",
+							Computed:    true,
+						},
+						"source": schema.StringAttribute{
+							Description: "One of `direct`, `group`, or `unknown` for a pair that cannot be traced to a direct permission or a group.",
+							Computed:    true,
+						},
+						"group_id": schema.StringAttribute{
+							Description: "The ID of the group granting the permission, when `source` is `group`.",
+							Computed:    true,
+						},
+						"group_name": schema.StringAttribute{
+							Description: "The name of the group granting the permission, when `source` is `group`.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"permission_names": schema.ListAttribute{
+				Description: "The sorted, distinct names of the effective permissions.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// ValidateConfig requires exactly one of id, username and email.
+func (d *userEffectivePermissionsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config userEffectivePermissionsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	set := 0
+	for _, value := range []types.String{config.ID, config.Username, config.Email} {
+		if value.IsUnknown() {
+			return
+		}
+		if !value.IsNull() {
+			set++
+		}
+	}
+
+	if set != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Imply User Lookup",
+			"Exactly one of id, username and email must be set to look up the user.",
+		)
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *userEffectivePermissionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state userEffectivePermissionsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userID := state.ID.ValueString()
+	if state.ID.IsNull() {
+		var err error
+		userID, err = d.findUserID(ctx, state.Username.ValueString(), state.Email.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Find Imply User", err.Error())
+			return
+		}
+	}
+
+	response, err := d.client.API.GetUserWithResponse(ctx, userID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Imply User", apiErrorDetail(err))
+		return
+	}
+
+	user, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Imply User", err.Error())
+		return
+	}
+
+	effectiveResponse, err := d.client.API.ListEffectivePermissionsWithResponse(ctx, userID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Imply Effective Permissions", apiErrorDetail(err))
+		return
+	}
+
+	effective, err := apiResult(effectiveResponse.JSON200, effectiveResponse.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Imply Effective Permissions", err.Error())
+		return
+	}
+
+	groups, err := d.userGroups(ctx, user)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Imply Group", apiErrorDetail(err))
+		return
+	}
+
+	state.ID = stringPointerValue(user.Id)
+	state.Username = stringPointerValue(user.Username)
+	state.Email = stringPointerValue(user.Email)
+	state.Permissions = resolveEffectivePermissions(user, groups, effective.Values)
+
+	names := map[string]bool{}
+	state.PermissionNames = []types.String{}
+	for _, permission := range state.Permissions {
+		if name := permission.Name.ValueString(); !names[name] {
+			names[name] = true
+			state.PermissionNames = append(state.PermissionNames, permission.Name)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *userEffectivePermissionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+// findUserID returns the ID of the single user with the given username or
+// email, compared case-insensitively.
+func (d *userEffectivePermissionsDataSource) findUserID(ctx context.Context, username, email string) (string, error) {
+	field, value := "username", username
+	if username == "" {
+		field, value = "email", email
+	}
+
+	users, err := listUsers(ctx, d.client, value, 0)
+	if err != nil {
+		return "", fmt.Errorf("%s", apiErrorDetail(err))
+	}
+
+	var ids []string
+	for _, user := range users {
+		candidate := user.Username
+		if field == "email" {
+			candidate = user.Email
+		}
+
+		if candidate != nil && strings.EqualFold(*candidate, value) && user.Id != nil {
+			ids = append(ids, *user.Id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no user has %s %q", field, value)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d users have %s %q, look the user up by id instead", len(ids), field, value)
+	}
+}
+
+// userGroups returns the groups of user with their permissions, reading
+// each group whose permissions the user representation leaves out.
+func (d *userEffectivePermissionsDataSource) userGroups(ctx context.Context, user *polarisapi.UserRepresentation) ([]polarisapi.GroupRepresentation, error) {
+	if user.Groups == nil {
+		return nil, nil
+	}
+
+	groups := make([]polarisapi.GroupRepresentation, 0, len(*user.Groups))
+	for _, group := range *user.Groups {
+		if group.Permissions == nil && group.Id != nil {
+			response, err := d.client.API.GetGroupWithResponse(ctx, *group.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			full, err := apiResult(response.JSON200, response.Body)
+			if err != nil {
+				return nil, err
+			}
+			group = *full
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// resolveEffectivePermissions expands the permissions a user holds into
+// permission and resource pairs, one per source. Pairs reported by the
+// effective permissions endpoint that match neither a direct permission nor
+// a permission of the user's groups are kept with an unknown source, so that
+// nothing the user holds is left out.
+func resolveEffectivePermissions(user *polarisapi.UserRepresentation, groups []polarisapi.GroupRepresentation, effective []polarisapi.PermissionRepresentation) []effectivePermissionModel {
+	items := []effectivePermissionModel{}
+	seen := map[string]bool{}
+
+	add := func(permissions *[]polarisapi.PermissionRepresentation, source string, group *polarisapi.GroupRepresentation) {
+		if permissions == nil {
+			return
+		}
+
+		for _, permission := range *permissions {
+			for _, resource := range permissionResources(permission) {
+				item := effectivePermissionModel{
+					Name:      stringPointerValue(permission.Name),
+					Resource:  resource,
+					Source:    types.StringValue(source),
+					GroupID:   types.StringNull(),
+					GroupName: types.StringNull(),
+				}
+				if group != nil {
+					item.GroupID = stringPointerValue(group.Id)
+					item.GroupName = stringPointerValue(group.Name)
+				}
+
+				seen[effectivePermissionKey(item)] = true
+				items = append(items, item)
+			}
+		}
+	}
+
+	add(user.Permissions, permissionSourceDirect, nil)
+	for i := range groups {
+		add(groups[i].Permissions, permissionSourceGroup, &groups[i])
+	}
+
+	for _, permission := range effective {
+		for _, resource := range permissionResources(permission) {
+			item := effectivePermissionModel{
+				Name:      stringPointerValue(permission.Name),
+				Resource:  resource,
+				Source:    types.StringValue(permissionSourceUnknown),
+				GroupID:   types.StringNull(),
+				GroupName: types.StringNull(),
+			}
+			if !seen[effectivePermissionKey(item)] {
+				seen[effectivePermissionKey(item)] = true
+				items = append(items, item)
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Name.ValueString() != b.Name.ValueString() {
+			return a.Name.ValueString() < b.Name.ValueString()
+		}
+		if a.Resource.ValueString() != b.Resource.ValueString() {
+			return a.Resource.ValueString() < b.Resource.ValueString()
+		}
+		if a.Source.ValueString() != b.Source.ValueString() {
+			return a.Source.ValueString() < b.Source.ValueString()
+		}
+		return a.GroupName.ValueString() < b.GroupName.ValueString()
+	})
+
+	return items
+}
+
+// permissionResources returns the resources a permission applies to, or a
+// single null resource when it is unscoped.
+func permissionResources(permission polarisapi.PermissionRepresentation) []types.String {
+	if permission.Resources == nil || len(*permission.Resources) == 0 {
+		return []types.String{types.StringNull()}
+	}
+
+	resources := make([]types.String, 0, len(*permission.Resources))
+	for _, resource := range *permission.Resources {
+		resources = append(resources, types.StringValue(resource))
+	}
+	return resources
+}
+
+// effectivePermissionKey identifies a permission and resource pair
+// regardless of its source.
+func effectivePermissionKey(item effectivePermissionModel) string {
+	return item.Name.ValueString() + "\x00" + item.Resource.ValueString() + "\x00" + fmt.Sprint(item.Resource.IsNull())
+}
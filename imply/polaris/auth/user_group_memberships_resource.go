@@ -0,0 +1,415 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &userGroupMembershipsResource{}
+	_ resource.ResourceWithConfigure   = &userGroupMembershipsResource{}
+	_ resource.ResourceWithImportState = &userGroupMembershipsResource{}
+)
+
+// privateManagedGroupIDsKey is the private state key holding the group_ids
+// last applied. In authoritative mode the group_ids attribute holds every
+// group the user is in, so it cannot tell which groups were configured.
+const privateManagedGroupIDsKey = "managed_group_ids"
+
+func NewUserGroupMembershipsResource() resource.Resource {
+	return &userGroupMembershipsResource{}
+}
+
+// userGroupMembershipsResource manages the groups a user belongs to from the
+// user's side. When authoritative, the user is removed on the next apply from
+// groups it was added to outside Terraform; otherwise only the listed groups
+// are managed.
+type userGroupMembershipsResource struct {
+	client *client.Client
+}
+
+type userGroupMembershipsResourceModel struct {
+	ID            types.String   `tfsdk:"id"`
+	UserID        types.String   `tfsdk:"user_id"`
+	GroupIDs      types.Set      `tfsdk:"group_ids"`
+	Authoritative types.Bool     `tfsdk:"authoritative"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *userGroupMembershipsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_group_memberships"
+}
+
+func (r *userGroupMembershipsResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the groups an Imply user belongs to, from the user's side. This suits user-centric " +
+			"configurations, such as one module per person listing their groups.\n\n" +
+			"With `authoritative = true`, the default, the user is removed on the next apply from any group " +
+			"they were added to outside Terraform. With `authoritative = false`, only the groups listed in " +
+			"`group_ids` are managed: memberships in other groups are left alone, and removing a group from " +
+			"`group_ids` removes the user from that group only. Switching from `authoritative = true` to " +
+			"`false` removes the user from no group.\n\n" +
+			"Do not combine an authoritative `imply_user_group_memberships` with `imply_group_members` or " +
+			"`imply_group_member` resources covering the same user, as they will undo each other's changes.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				Description: "The ID of the user whose group memberships are managed. Changing it replaces the resource.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group_ids": schema.SetAttribute{
+				Description: "The IDs of the groups the user belongs to.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"authoritative": schema.BoolAttribute{
+				Description: "Whether to remove the user from groups not listed in `group_ids`. Defaults to `true`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *userGroupMembershipsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "create the Imply user group memberships") {
+		return
+	}
+
+	var plan userGroupMembershipsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if !r.reconcile(ctx, plan, nil, &resp.Diagnostics) {
+		return
+	}
+
+	plan.ID = plan.UserID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setManagedGroupIDs(ctx, resp.Private, plan.GroupIDs)...)
+}
+
+func (r *userGroupMembershipsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userGroupMembershipsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	current, err := userGroupIDs(ctx, r.client, state.UserID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			tflog.Warn(ctx, "Imply user no longer exists, removing its group memberships from state", map[string]any{
+				"user_id": state.UserID.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to Read Imply User Group Memberships", apiErrorDetail(err))
+		return
+	}
+
+	// Imported memberships have no authoritative setting yet.
+	if state.Authoritative.IsNull() {
+		state.Authoritative = types.BoolValue(true)
+	}
+
+	// Outside authoritative mode only the managed groups are tracked, so
+	// that the user leaving one of them still shows as drift.
+	groupIDs := current
+	if !state.Authoritative.ValueBool() {
+		managed, diags := managedGroupIDs(ctx, req.Private, state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		groupIDs = setIntersection(managed, current)
+	}
+
+	groupIDsValue, diags := types.SetValueFrom(ctx, types.StringType, groupIDs)
+	resp.Diagnostics.Append(diags...)
+
+	state.ID = state.UserID
+	state.GroupIDs = groupIDsValue
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *userGroupMembershipsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "update the Imply user group memberships") {
+		return
+	}
+
+	var plan userGroupMembershipsResourceModel
+	var state userGroupMembershipsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Leaving authoritative mode removes the user from no group: the
+	// previous mode already owned every membership, and the new one only
+	// takes over the listed groups.
+	var managed []string
+	if !state.Authoritative.ValueBool() {
+		managed, diags = managedGroupIDs(ctx, req.Private, state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if !r.reconcile(ctx, plan, managed, &resp.Diagnostics) {
+		return
+	}
+
+	plan.ID = plan.UserID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setManagedGroupIDs(ctx, resp.Private, plan.GroupIDs)...)
+}
+
+// Delete removes the user from the groups this resource manages.
+func (r *userGroupMembershipsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "delete the Imply user group memberships") {
+		return
+	}
+
+	var state userGroupMembershipsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var managed []string
+	resp.Diagnostics.Append(state.GroupIDs.ElementsAs(ctx, &managed, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	userID := state.UserID.ValueString()
+
+	current, err := userGroupIDs(ctx, r.client, userID)
+	if err != nil {
+		if !client.IsNotFound(err) {
+			resp.Diagnostics.AddError("Unable to Read Imply User Group Memberships", apiErrorDetail(err))
+		}
+		return
+	}
+
+	for _, groupID := range setIntersection(managed, current) {
+		err := removeGroupMembers(ctx, r.client, groupID, []string{userID})
+		if err != nil && !client.IsNotFound(err) {
+			resp.Diagnostics.AddError("Unable to Remove Imply Group Member", apiErrorDetail(err))
+			return
+		}
+	}
+}
+
+// ImportState imports the group memberships of a user by the user's ID, in
+// authoritative mode.
+func (r *userGroupMembershipsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("authoritative"), true)...)
+}
+
+func (r *userGroupMembershipsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// reconcile adds the user to the planned groups it is missing from and
+// removes it from the groups it should leave: every other group when
+// authoritative, and otherwise only the previously managed groups that are
+// no longer listed.
+func (r *userGroupMembershipsResource) reconcile(ctx context.Context, plan userGroupMembershipsResourceModel, managed []string, diags *diag.Diagnostics) bool {
+	var desired []string
+	diags.Append(plan.GroupIDs.ElementsAs(ctx, &desired, false)...)
+	if diags.HasError() {
+		return false
+	}
+
+	userID := plan.UserID.ValueString()
+
+	current, err := userGroupIDs(ctx, r.client, userID)
+	if err != nil {
+		diags.AddError("Unable to Read Imply User Group Memberships", apiErrorDetail(err))
+		return false
+	}
+
+	join := setDifference(desired, current)
+
+	var leave []string
+	if plan.Authoritative.ValueBool() {
+		leave = setDifference(current, desired)
+	} else {
+		// Groups no longer listed, limited to those the user still is in.
+		leave = setIntersection(setDifference(managed, desired), current)
+	}
+
+	tflog.Debug(ctx, "Reconciling Imply user group memberships", map[string]any{
+		"user_id": userID,
+		"join":    join,
+		"leave":   leave,
+	})
+
+	for _, groupID := range join {
+		if err := addGroupMembers(ctx, r.client, groupID, []string{userID}); err != nil {
+			diags.AddError("Unable to Add Imply Group Member", apiErrorDetail(err))
+			return false
+		}
+	}
+
+	for _, groupID := range leave {
+		if err := removeGroupMembers(ctx, r.client, groupID, []string{userID}); err != nil {
+			diags.AddError("Unable to Remove Imply Group Member", apiErrorDetail(err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// managedGroupIDs returns the group_ids last applied, stored in private
+// state. State written before they were stored falls back to the group_ids
+// attribute, which outside authoritative mode only holds managed groups.
+func managedGroupIDs(ctx context.Context, private privateStateGetter, state userGroupMembershipsResourceModel) ([]string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateManagedGroupIDsKey)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var managed []string
+	if len(value) == 0 {
+		diags.Append(state.GroupIDs.ElementsAs(ctx, &managed, false)...)
+		return managed, diags
+	}
+
+	if err := json.Unmarshal(value, &managed); err != nil {
+		diags.AddError("Invalid Private State", "Unable to decode the managed group IDs: "+err.Error())
+		return nil, diags
+	}
+
+	return managed, diags
+}
+
+// setManagedGroupIDs stores the applied group_ids in private state.
+func setManagedGroupIDs(ctx context.Context, private privateStateSetter, groupIDs types.Set) diag.Diagnostics {
+	var managed []string
+	diags := groupIDs.ElementsAs(ctx, &managed, false)
+	if diags.HasError() {
+		return diags
+	}
+
+	value, err := json.Marshal(setDifference(managed, nil))
+	if err != nil {
+		diags.AddError("Invalid Private State", "Unable to encode the managed group IDs: "+err.Error())
+		return diags
+	}
+
+	diags.Append(private.SetKey(ctx, privateManagedGroupIDsKey, value)...)
+	return diags
+}
+
+// userGroupIDs returns the IDs of the groups a user belongs to.
+func userGroupIDs(ctx context.Context, c *client.Client, userID string) ([]string, error) {
+	response, err := c.API.GetUserWithResponse(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	if user.Groups != nil {
+		for _, group := range *user.Groups {
+			if group.Id != nil && *group.Id != "" {
+				ids = append(ids, *group.Id)
+			}
+		}
+	}
+
+	return ids, nil
+}
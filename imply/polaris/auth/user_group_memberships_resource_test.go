@@ -0,0 +1,314 @@
+// Copyright (c) HashiCorp, Inc.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// membershipsServer serves one user's group memberships, applying the
+// members endpoints to them and recording the groups the user was removed
+// from.
+type membershipsServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	groups  []string
+	removed []string
+}
+
+func newMembershipsServer(t *testing.T, userID string) *membershipsServer {
+	t.Helper()
+
+	s := &membershipsServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/"+userID):
+			groups := []map[string]string{}
+			for _, groupID := range s.groups {
+				groups = append(groups, map[string]string{"id": groupID})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": userID, "groups": groups})
+
+		case strings.HasSuffix(r.URL.Path, "/members"):
+			groupID := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/groups/")+len("/groups/"):], "/members")
+			if r.Method == http.MethodPost {
+				s.groups = setDifference(append(s.groups, groupID), nil)
+			} else {
+				s.groups = setDifference(s.groups, []string{groupID})
+				s.removed = append(s.removed, groupID)
+			}
+			_, _ = w.Write([]byte(`[]`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": "NotFound", "message": "Not found."}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// join adds the user to a group outside Terraform.
+func (s *membershipsServer) join(groupID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groups = setDifference(append(s.groups, groupID), nil)
+}
+
+// memberships returns the user's groups and the groups it was removed from.
+func (s *membershipsServer) memberships() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.groups), slices.Clone(s.removed)
+}
+
+// testProvider hands a client to the resources of this package, so that
+// they can be driven through the plugin protocol with their private state.
+type testProvider struct {
+	client *client.Client
+}
+
+func (p *testProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "imply"
+}
+
+func (p *testProvider) Schema(context.Context, provider.SchemaRequest, *provider.SchemaResponse) {}
+
+func (p *testProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	resp.ResourceData = p.client
+}
+
+func (p *testProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{NewUserGroupMembershipsResource}
+}
+
+func (p *testProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// membershipsHarness applies imply_user_group_memberships configurations
+// through the plugin protocol, carrying state and private state between
+// operations as Terraform does.
+type membershipsHarness struct {
+	t          *testing.T
+	server     tfprotov6.ProviderServer
+	objectType tftypes.Object
+	state      *tfprotov6.DynamicValue
+	private    []byte
+}
+
+func newMembershipsHarness(t *testing.T, url string) *membershipsHarness {
+	t.Helper()
+
+	apiKey := "test-key"
+	c, err := client.NewClient(nil, &apiKey, client.WithAPIURL(url))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := context.Background()
+	server := providerserver.NewProtocol6(&testProvider{client: c})()
+
+	config, err := tfprotov6.NewDynamicValue(tftypes.Object{}, tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{}))
+	if err != nil {
+		t.Fatalf("NewDynamicValue: %v", err)
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	if err != nil || len(configured.Diagnostics) > 0 {
+		t.Fatalf("ConfigureProvider: %v %v", err, configured.Diagnostics)
+	}
+
+	var schemaResp resource.SchemaResponse
+	NewUserGroupMembershipsResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	return &membershipsHarness{
+		t:          t,
+		server:     server,
+		objectType: schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object),
+	}
+}
+
+// value returns a configuration or planned state of the resource.
+func (h *membershipsHarness) value(groupIDs []string, authoritative bool, id tftypes.Value) *tfprotov6.DynamicValue {
+	h.t.Helper()
+
+	groups := make([]tftypes.Value, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		groups = append(groups, tftypes.NewValue(tftypes.String, groupID))
+	}
+
+	value, err := tfprotov6.NewDynamicValue(h.objectType, tftypes.NewValue(h.objectType, map[string]tftypes.Value{
+		"id":            id,
+		"user_id":       tftypes.NewValue(tftypes.String, "u1"),
+		"group_ids":     tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, groups),
+		"authoritative": tftypes.NewValue(tftypes.Bool, authoritative),
+		"timeouts":      tftypes.NewValue(h.objectType.AttributeTypes["timeouts"], nil),
+	}))
+	if err != nil {
+		h.t.Fatalf("NewDynamicValue: %v", err)
+	}
+
+	return &value
+}
+
+// apply creates or updates the resource with the given configuration.
+func (h *membershipsHarness) apply(groupIDs []string, authoritative bool) {
+	h.t.Helper()
+
+	prior := h.state
+	id := tftypes.NewValue(tftypes.String, "u1")
+	if prior == nil {
+		null, err := tfprotov6.NewDynamicValue(h.objectType, tftypes.NewValue(h.objectType, nil))
+		if err != nil {
+			h.t.Fatalf("NewDynamicValue: %v", err)
+		}
+		prior = &null
+		id = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	}
+
+	resp, err := h.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       "imply_user_group_memberships",
+		PriorState:     prior,
+		PlannedState:   h.value(groupIDs, authoritative, id),
+		Config:         h.value(groupIDs, authoritative, tftypes.NewValue(tftypes.String, nil)),
+		PlannedPrivate: h.private,
+	})
+	if err != nil || len(resp.Diagnostics) > 0 {
+		h.t.Fatalf("ApplyResourceChange: %v %v", err, diagnosticSummaries(resp.Diagnostics))
+	}
+
+	h.state, h.private = resp.NewState, resp.Private
+}
+
+// refresh reads the resource, as Terraform does before planning.
+func (h *membershipsHarness) refresh() []string {
+	h.t.Helper()
+
+	resp, err := h.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     "imply_user_group_memberships",
+		CurrentState: h.state,
+		Private:      h.private,
+	})
+	if err != nil || len(resp.Diagnostics) > 0 {
+		h.t.Fatalf("ReadResource: %v %v", err, diagnosticSummaries(resp.Diagnostics))
+	}
+	h.state, h.private = resp.NewState, resp.Private
+
+	state, err := h.state.Unmarshal(h.objectType)
+	if err != nil {
+		h.t.Fatalf("Unmarshal: %v", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		h.t.Fatalf("As: %v", err)
+	}
+	var groups []tftypes.Value
+	if err := attributes["group_ids"].As(&groups); err != nil {
+		h.t.Fatalf("As: %v", err)
+	}
+
+	groupIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		var groupID string
+		if err := group.As(&groupID); err != nil {
+			h.t.Fatalf("As: %v", err)
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+	return setDifference(groupIDs, nil)
+}
+
+func diagnosticSummaries(diags []*tfprotov6.Diagnostic) []string {
+	result := make([]string, 0, len(diags))
+	for _, d := range diags {
+		result = append(result, d.Summary+": "+d.Detail)
+	}
+	return result
+}
+
+func TestUserGroupMembershipsLeavingAuthoritativeMode(t *testing.T) {
+	server := newMembershipsServer(t, "u1")
+	h := newMembershipsHarness(t, server.URL)
+
+	h.apply([]string{"analysts"}, true)
+	server.join("admins")
+
+	if got := h.refresh(); !slices.Equal(got, []string{"admins", "analysts"}) {
+		t.Fatalf("authoritative group_ids = %q, want every group of the user", got)
+	}
+
+	h.apply([]string{"analysts"}, false)
+
+	groups, removed := server.memberships()
+	if len(removed) > 0 {
+		t.Errorf("removed from %q, want no group left when leaving authoritative mode", removed)
+	}
+	if !slices.Equal(groups, []string{"admins", "analysts"}) {
+		t.Errorf("groups = %q, want the user still in admins and analysts", groups)
+	}
+
+	if got := h.refresh(); !slices.Equal(got, []string{"analysts"}) {
+		t.Errorf("non-authoritative group_ids = %q, want only the configured group", got)
+	}
+}
+
+func TestUserGroupMembershipsNonAuthoritative(t *testing.T) {
+	server := newMembershipsServer(t, "u1")
+	h := newMembershipsHarness(t, server.URL)
+
+	h.apply([]string{"analysts", "engineers"}, false)
+	server.join("admins")
+	h.refresh()
+
+	h.apply([]string{"analysts"}, false)
+
+	groups, removed := server.memberships()
+	if !slices.Equal(removed, []string{"engineers"}) {
+		t.Errorf("removed from %q, want only the group dropped from group_ids", removed)
+	}
+	if !slices.Equal(groups, []string{"admins", "analysts"}) {
+		t.Errorf("groups = %q, want the user still in admins and analysts", groups)
+	}
+}
+
+func TestUserGroupMembershipsAuthoritative(t *testing.T) {
+	server := newMembershipsServer(t, "u1")
+	h := newMembershipsHarness(t, server.URL)
+
+	h.apply([]string{"analysts"}, false)
+	server.join("admins")
+	h.refresh()
+
+	h.apply([]string{"analysts"}, true)
+
+	groups, removed := server.memberships()
+	if !slices.Equal(removed, []string{"admins"}) {
+		t.Errorf("removed from %q, want the group joined outside Terraform", removed)
+	}
+	if !slices.Equal(groups, []string{"analysts"}) {
+		t.Errorf("groups = %q, want only analysts", groups)
+	}
+}
@@ -3,22 +3,32 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/arimal199/terraform-provider-imply/imply/client"
+	"github.com/arimal199/terraform-provider-imply/imply/polarisapi"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource                = &userResource{}
-	_ resource.ResourceWithConfigure   = &userResource{}
-	_ resource.ResourceWithImportState = &userResource{}
+	_ resource.Resource                   = &userResource{}
+	_ resource.ResourceWithConfigure      = &userResource{}
+	_ resource.ResourceWithImportState    = &userResource{}
+	_ resource.ResourceWithValidateConfig = &userResource{}
+	_ resource.ResourceWithModifyPlan     = &userResource{}
 )
 
 func NewUserResource() resource.Resource {
@@ -30,25 +40,33 @@ type userResource struct {
 }
 
 type userResourceModel struct {
-	ID            types.String      `tfsdk:"id"`
-	Username      types.String      `tfsdk:"username"`
-	Email         types.String      `tfsdk:"email"`
-	FirstName     types.String      `tfsdk:"first_name"`
-	LastName      types.String      `tfsdk:"last_name"`
-	Enabled       types.Bool        `tfsdk:"enabled"`
-	EmailVerified types.Bool        `tfsdk:"email_verified"`
-	Permissions   []PermissionModel `tfsdk:"permissions"`
-	Groups        []GroupModel      `tfsdk:"groups"`
-	Actions       []types.String    `tfsdk:"actions"`
-	CreatedOn     types.String      `tfsdk:"created_on"`
+	ID            types.String   `tfsdk:"id"`
+	Username      types.String   `tfsdk:"username"`
+	Email         types.String   `tfsdk:"email"`
+	FirstName     types.String   `tfsdk:"first_name"`
+	LastName      types.String   `tfsdk:"last_name"`
+	Enabled       types.Bool     `tfsdk:"enabled"`
+	EmailVerified types.Bool     `tfsdk:"email_verified"`
+	Permissions   types.Set      `tfsdk:"permissions"`
+	Groups        []GroupModel   `tfsdk:"groups"`
+	Actions       []types.String `tfsdk:"actions"`
+	CreatedOn     types.String   `tfsdk:"created_on"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_user"
 }
 
-func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *userResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages an Imply user and, optionally, the permissions assigned to it directly.\n\n" +
+			"`permissions` holds only the permissions assigned to the user directly. Permissions the user " +
+			"inherits from groups are reported under `groups`, so changing a group's permissions never " +
+			"causes a diff on `permissions`. When `permissions` is set, it is authoritative: direct " +
+			"permissions assigned outside Terraform show up as drift and are removed on the next apply. " +
+			"Set `permissions = []` to revoke every direct permission. When `permissions` is left out, " +
+			"the user's direct permissions are not managed and are only reported.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -75,19 +93,10 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"email_verified": schema.BoolAttribute{
 				Computed: true,
 			},
-			"permissions": schema.ListNestedAttribute{
-				Computed: true,
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"id":   schema.StringAttribute{Computed: true},
-						"name": schema.StringAttribute{Computed: true},
-						"resources": schema.ListAttribute{
-							Computed:    true,
-							ElementType: types.StringType,
-						},
-					},
-				},
-			},
+			// Only the permissions assigned to the user directly; those
+			// inherited from groups are reported under groups, so that
+			// changing a group's permissions never shows up here.
+			"permissions": permissionGrantsAttribute("The permissions assigned to the user directly, validated against the permissions returned by `GET /v1/permissions`."),
 			"groups": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -120,38 +129,69 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
 func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "create the Imply user") {
+		return
+	}
+
 	var plan userResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	body := map[string]any{
-		"username": plan.Username.ValueString(),
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.FirstName.IsNull() {
-		body["firstName"] = plan.FirstName.ValueString()
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	body := polarisapi.UserRepresentation{
+		Username: plan.Username.ValueStringPointer(),
+	}
+
+	if !plan.FirstName.IsNull() && !plan.FirstName.IsUnknown() {
+		body.FirstName = plan.FirstName.ValueStringPointer()
 	}
-	if !plan.LastName.IsNull() {
-		body["lastName"] = plan.LastName.ValueString()
+	if !plan.LastName.IsNull() && !plan.LastName.IsUnknown() {
+		body.LastName = plan.LastName.ValueStringPointer()
+	}
+	if !plan.Enabled.IsNull() && !plan.Enabled.IsUnknown() {
+		body.Enabled = plan.Enabled.ValueBoolPointer()
+	}
+
+	permissions, diags := expandPermissionGrants(ctx, plan.Permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if !plan.Enabled.IsNull() {
-		body["enabled"] = plan.Enabled.ValueBool()
+	body.Permissions = permissions
+
+	response, err := r.client.API.CreateUserWithResponse(ctx, nil, body)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Imply User", apiErrorDetail(err))
+		return
 	}
 
-	user, err := r.client.Post("/users", body)
+	user, err := apiResult(response.JSON201, response.Body)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to Create Imply User", err.Error())
 		return
 	}
 
-	state := flattenUserResource(plan, user)
+	state, diags := flattenUserResource(plan, user)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, client.ETag(response.HTTPResponse))...)
 }
 
 func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -161,22 +201,43 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	user, err := r.client.Get(fmt.Sprintf("/users/%s", state.ID.ValueString()))
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	response, err := r.client.API.GetUserWithResponse(ctx, state.ID.ValueString())
 	if err != nil {
-		if isNotFoundError(err) {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
 
+		resp.Diagnostics.AddError("Unable to Read Imply User", apiErrorDetail(err))
+		return
+	}
+
+	user, err := apiResult(response.JSON200, response.Body)
+	if err != nil {
 		resp.Diagnostics.AddError("Unable to Read Imply User", err.Error())
 		return
 	}
 
-	state = flattenUserResource(state, user)
+	state, diags = flattenUserResource(state, user)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, client.ETag(response.HTTPResponse))...)
 }
 
 func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "update the Imply user") {
+		return
+	}
+
 	var plan userResourceModel
 	var state userResourceModel
 
@@ -186,37 +247,165 @@ func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	body := map[string]any{}
-	if !plan.FirstName.IsNull() {
-		body["firstName"] = plan.FirstName.ValueString()
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if !plan.LastName.IsNull() {
-		body["lastName"] = plan.LastName.ValueString()
+
+	etag, diags := privateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if !plan.Enabled.IsNull() {
-		body["enabled"] = plan.Enabled.ValueBool()
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// The users API only accepts a full PUT, so the whole desired document
+	// is sent, and only when an attribute changed. Attributes the plan
+	// leaves unknown keep their current value.
+	desired := userDocument(plan)
+	if plan.Enabled.IsUnknown() && !state.Enabled.IsNull() {
+		desired["enabled"] = state.Enabled.ValueBool()
 	}
 
-	user, err := r.client.Put(fmt.Sprintf("/users/%s", state.ID.ValueString()), body)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to Update Imply User", err.Error())
+	prior := userDocument(state)
+
+	// Permissions left out of the configuration are not managed, even
+	// though the plan carries the current ones over from state.
+	var configured types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &configured)...)
+	if !configured.IsNull() {
+		priorPermissions, diags := expandPermissionGrants(ctx, state.Permissions)
+		resp.Diagnostics.Append(diags...)
+		permissions, diags := expandPermissionGrants(ctx, configured)
+		resp.Diagnostics.Append(diags...)
+
+		if priorPermissions != nil {
+			prior["permissions"] = *priorPermissions
+		}
+		if permissions != nil {
+			desired["permissions"] = *permissions
+		}
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	nextState := flattenUserResource(plan, user)
+	changed := client.MergePatch(prior, desired)
+
+	var user *polarisapi.UserRepresentation
+	var nextETag string
+	if len(changed) == 0 {
+		// Nothing but Terraform-only settings such as timeouts changed.
+		response, err := r.client.API.GetUserWithResponse(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Read Imply User", apiErrorDetail(err))
+			return
+		}
+
+		user, err = apiResult(response.JSON200, response.Body)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Read Imply User", err.Error())
+			return
+		}
+		nextETag = client.ETag(response.HTTPResponse)
+	} else {
+		body, err := json.Marshal(desired)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Update Imply User", "Error encoding the user: "+err.Error())
+			return
+		}
+
+		tflog.Debug(ctx, "Updating changed Imply user attributes", map[string]any{
+			"id":     state.ID.ValueString(),
+			"fields": slices.Sorted(maps.Keys(changed)),
+		})
+
+		response, err := r.client.API.UpdateUserWithBodyWithResponse(ctx, state.ID.ValueString(), "application/json", bytes.NewReader(body), client.IfMatch(etag))
+		if err != nil {
+			if checkPreconditionFailed(&resp.Diagnostics, err, "Imply User Changed Outside Terraform", "user") {
+				return
+			}
+
+			resp.Diagnostics.AddError("Unable to Update Imply User", apiErrorDetail(err))
+			return
+		}
+
+		user, err = apiResult(response.JSON200, response.Body)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Update Imply User", err.Error())
+			return
+		}
+		nextETag = client.ETag(response.HTTPResponse)
+	}
+
+	nextState, diags := flattenUserResource(plan, user)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &nextState)...)
+	resp.Diagnostics.Append(setPrivateETag(ctx, resp.Private, nextETag)...)
 }
 
 func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !checkWritable(r.client, &resp.Diagnostics, "delete the Imply user") {
+		return
+	}
+
 	var state userResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := r.client.Delete(fmt.Sprintf("/users/%s", state.ID.ValueString())); err != nil && !isNotFoundError(err) {
-		resp.Diagnostics.AddError("Unable to Delete Imply User", err.Error())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	etag, diags := privateETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_, err := r.client.API.DeleteUserWithResponse(ctx, state.ID.ValueString(), client.IfMatch(etag))
+	if err != nil && !client.IsNotFound(err) {
+		if checkPreconditionFailed(&resp.Diagnostics, err, "Imply User Changed Outside Terraform", "user") {
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to Delete Imply User", apiErrorDetail(err))
+	}
+}
+
+func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var permissions types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &permissions)...)
+	resp.Diagnostics.Append(validatePermissionGrants(ctx, path.Root("permissions"), permissions)...)
+}
+
+// ModifyPlan checks changed permission names against the Imply permission
+// catalog, so that a typo fails the plan rather than the apply.
+func (r *userResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned, prior types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("permissions"), &planned)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("permissions"), &prior)...)
+	}
+	if resp.Diagnostics.HasError() || planned.Equal(prior) {
+		return
+	}
+
+	resp.Diagnostics.Append(checkPermissionNames(ctx, r.client, path.Root("permissions"), planned)...)
 }
 
 func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -240,18 +429,37 @@ func (r *userResource) Configure(_ context.Context, req resource.ConfigureReques
 	r.client = client
 }
 
-func flattenUserResource(plan userResourceModel, user map[string]any) userResourceModel {
+// userDocument returns the writable attributes of model that are set, keyed
+// by their UserRepresentation field names, for building update bodies and
+// detecting changes with client.MergePatch.
+func userDocument(model userResourceModel) map[string]any {
+	document := map[string]any{}
+	if !model.FirstName.IsNull() && !model.FirstName.IsUnknown() {
+		document["firstName"] = model.FirstName.ValueString()
+	}
+	if !model.LastName.IsNull() && !model.LastName.IsUnknown() {
+		document["lastName"] = model.LastName.ValueString()
+	}
+	if !model.Enabled.IsNull() && !model.Enabled.IsUnknown() {
+		document["enabled"] = model.Enabled.ValueBool()
+	}
+	return document
+}
+
+func flattenUserResource(plan userResourceModel, user *polarisapi.UserRepresentation) (userResourceModel, diag.Diagnostics) {
 	state := plan
-	state.ID = stringValue(user, "id")
-	state.Username = stringValue(user, "username")
-	state.Email = stringValue(user, "email")
-	state.FirstName = stringValue(user, "firstName")
-	state.LastName = stringValue(user, "lastName")
-	state.Enabled = boolValue(user, "enabled")
-	state.EmailVerified = boolValue(user, "emailVerified")
-	state.Permissions = permissionModels(user["permissions"])
-	state.Groups = groupModels(user["groups"])
-	state.Actions = stringModels(user["actions"], "")
-	state.CreatedOn = stringValue(user, "createdOn")
-	return state
+	state.ID = stringPointerValue(user.Id)
+	state.Username = stringPointerValue(user.Username)
+	state.Email = stringPointerValue(user.Email)
+	state.FirstName = stringPointerValue(user.FirstName)
+	state.LastName = stringPointerValue(user.LastName)
+	state.Enabled = types.BoolPointerValue(user.Enabled)
+	state.EmailVerified = types.BoolPointerValue(user.EmailVerified)
+	state.Groups = apiGroupModels(user.Groups)
+	state.Actions = apiActionModels(user.Actions)
+	state.CreatedOn = timePointerValue(user.CreatedOn)
+
+	permissions, diags := flattenPermissionGrants(user.Permissions)
+	state.Permissions = permissions
+	return state, diags
 }
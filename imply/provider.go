@@ -4,16 +4,22 @@ package imply
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arimal199/terraform-provider-imply/imply/client"
 	"github.com/arimal199/terraform-provider-imply/imply/polaris/auth"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -32,10 +38,43 @@ func New(version string) func() provider.Provider {
 
 // implyProviderModel maps provider schema data to a Go type.
 type implyProviderModel struct {
-	Host   types.String `tfsdk:"host"`
-	ApiKey types.String `tfsdk:"api_key"`
+	Host          types.String `tfsdk:"host"`
+	ApiURL        types.String `tfsdk:"api_url"`
+	ApiKey        types.String `tfsdk:"api_key"`
+	ApiKeyFile    types.String `tfsdk:"api_key_file"`
+	ApiKeyCommand types.List   `tfsdk:"api_key_command"`
+	MaxRetries    types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait  types.String `tfsdk:"retry_max_wait"`
+	ProjectID     types.String `tfsdk:"project_id"`
+	Region        types.String `tfsdk:"region"`
+	AuthMethod    types.String `tfsdk:"auth_method"`
+	ClientID      types.String `tfsdk:"client_id"`
+	ClientSecret  types.String `tfsdk:"client_secret"`
+	TokenURL      types.String `tfsdk:"token_url"`
+
+	Profile         types.String `tfsdk:"profile"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+
+	ReadOnly            types.Bool `tfsdk:"read_only"`
+	ValidateCredentials types.Bool `tfsdk:"validate_credentials"`
 }
 
+// Authentication methods accepted by the auth_method attribute.
+const (
+	authMethodAPIKey = "api_key"
+	authMethodOAuth  = "oauth"
+)
+
 // implyProvider is the provider implementation.
 type implyProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -56,12 +95,107 @@ func (p *implyProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional:    true,
-				Description: "The Imply API host. Can be set via IMPLY_HOST environment variable.",
+				Description: "The Imply API host, such as `ORG.api.imply.io`. The console address `ORG.app.imply.io` is accepted and rewritten to the API address. Can be set via IMPLY_HOST environment variable.",
+			},
+			"api_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "The full base URL of the Imply API, such as `https://imply.example.com` or `http://localhost:8080`. Takes precedence over `host` and is used without any host or region rewriting. Can be set via IMPLY_API_URL environment variable.",
 			},
 			"api_key": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "The Imply API key. Can be set via IMPLY_API_KEY environment variable.",
+				Description: "The Imply API key, used when `auth_method` is `api_key`. Can be set via IMPLY_API_KEY environment variable.",
+			},
+			"api_key_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of a file holding the Imply API key, such as one written by a secrets agent. Conflicts with `api_key` and `api_key_command` set in the same place, and replaces them when set in a higher-precedence one. Can be set via IMPLY_API_KEY_FILE environment variable.",
+			},
+			"api_key_command": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Credential helper command, as the executable followed by its arguments, that prints the Imply API key as JSON: `{\"key\": \"...\", \"expiry\": \"2024-01-02T15:04:05Z\"}`. `expiry` is optional; the command is run again shortly before the key expires. Conflicts with `api_key` and `api_key_file` set in the same place, and replaces them when set in a higher-precedence one. Can be set via IMPLY_API_KEY_COMMAND environment variable, with the arguments separated by spaces.",
+			},
+			"auth_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "How requests are authenticated: `api_key` to send the API key, or `oauth` to exchange `client_id` and `client_secret` for an access token at `token_url` (OAuth client credentials). Defaults to `api_key`. Can be set via IMPLY_AUTH_METHOD environment variable.",
+			},
+			"client_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OAuth client ID, used when `auth_method` is `oauth`. Can be set via IMPLY_CLIENT_ID environment variable.",
+			},
+			"client_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The OAuth client secret, used when `auth_method` is `oauth`. Can be set via IMPLY_CLIENT_SECRET environment variable.",
+			},
+			"token_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OAuth token endpoint, such as `https://id.imply.io/auth/realms/ORG/protocol/openid-connect/token`, used when `auth_method` is `oauth`. Access tokens are cached and refreshed before they expire. Can be set via IMPLY_TOKEN_URL environment variable.",
+			},
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the profile in the credentials file that settings not given in the configuration or environment are read from. Defaults to `default`. Can be set via IMPLY_PROFILE environment variable.",
+			},
+			"credentials_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of the INI credentials file holding named profiles. Defaults to `~/.imply/credentials`. Can be set via IMPLY_CREDENTIALS_FILE environment variable.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM file of certificate authorities to trust in addition to the system ones, such as that of a TLS-intercepting proxy. Can be set via IMPLY_CA_CERT_FILE environment variable.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM-encoded certificate authorities to trust in addition to the system ones. Can be set via IMPLY_CA_CERT_PEM environment variable.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "Client certificate presented for mutual TLS, as PEM content or the path of a PEM file. Requires `client_key`. Can be set via IMPLY_CLIENT_CERT environment variable.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Private key of `client_cert`, as PEM content or the path of a PEM file. Can be set via IMPLY_CLIENT_KEY environment variable.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Disables verification of the API's TLS certificate. Only intended for debugging; prefer `ca_cert_file` or `ca_cert_pem`. Can be set via IMPLY_INSECURE_SKIP_VERIFY environment variable.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the HTTP(S) or SOCKS5 proxy to send every request through. Defaults to the proxy from the HTTPS_PROXY and NO_PROXY environment variables. Can be set via IMPLY_PROXY_URL environment variable.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Maximum number of times a throttled (429) or failed (5xx) request is retried. Defaults to %d. Set to 0 to disable retries.", client.DefaultMaxRetries),
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Maximum wait between two retries, as a duration such as `30s` or `2m`. Also caps waits requested through `Retry-After`. Defaults to `%s`.", client.DefaultRetryMaxWait),
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests per second sent to the Imply API, shared by every resource and data source, including retries. Defaults to `0`, which means unlimited.",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Refuses every change: resources fail before sending any request from create, update and delete, and the API client rejects POST, PUT, PATCH and DELETE requests. Intended for plan-only pipelines using production credentials. Can be set via IMPLY_READ_ONLY environment variable.",
+			},
+			"validate_credentials": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Checks the credentials against `/v1/apikeyinfo` when the provider is configured, reporting invalid or unauthorized credentials once instead of in every resource. Defaults to `false`. Can be set via IMPLY_VALIDATE_CREDENTIALS environment variable.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests to the Imply API in flight at once, whatever Terraform's `-parallelism`. Defaults to `0`, which means unlimited.",
+			},
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Polaris project ID that project-scoped requests are sent to. Can be set via IMPLY_PROJECT_ID environment variable.",
+			},
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Description: "The region the projects are hosted in, such as `us-east-1`. Project-scoped requests are sent to the regional API host `ORG.REGION.aws.api.imply.io`. Can be set via IMPLY_REGION environment variable.",
 			},
 		},
 	}
@@ -88,6 +222,15 @@ func (p *implyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	if config.ApiURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_url"),
+			"Unknown Imply API URL",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply API URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_API_URL environment variable.",
+		)
+	}
+
 	if config.ApiKey.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_key"),
@@ -97,44 +240,478 @@ func (p *implyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	if config.ApiKeyFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_key_file"),
+			"Unknown Imply API Key File",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply API key file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_API_KEY_FILE environment variable.",
+		)
+	}
+
+	if config.ApiKeyCommand.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_key_command"),
+			"Unknown Imply API Key Command",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply API key command. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_API_KEY_COMMAND environment variable.",
+		)
+	}
+
+	if config.AuthMethod.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_method"),
+			"Unknown Imply Authentication Method",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply authentication method. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_AUTH_METHOD environment variable.",
+		)
+	}
+
+	if config.ClientID.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_id"),
+			"Unknown Imply OAuth Client ID",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply OAuth client ID. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CLIENT_ID environment variable.",
+		)
+	}
+
+	if config.ClientSecret.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_secret"),
+			"Unknown Imply OAuth Client Secret",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply OAuth client secret. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CLIENT_SECRET environment variable.",
+		)
+	}
+
+	if config.TokenURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_url"),
+			"Unknown Imply OAuth Token URL",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply OAuth token URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_TOKEN_URL environment variable.",
+		)
+	}
+
+	if config.Profile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown Imply Credentials Profile",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the credentials profile. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_PROFILE environment variable.",
+		)
+	}
+
+	if config.CredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credentials_file"),
+			"Unknown Imply Credentials File",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the credentials file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CREDENTIALS_FILE environment variable.",
+		)
+	}
+
+	if config.CACertFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_file"),
+			"Unknown Imply CA Certificate File",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the CA certificate file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CA_CERT_FILE environment variable.",
+		)
+	}
+
+	if config.CACertPEM.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_pem"),
+			"Unknown Imply CA Certificate PEM",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the CA certificate PEM. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CA_CERT_PEM environment variable.",
+		)
+	}
+
+	if config.ClientCert.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Unknown Imply Client Certificate",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the client certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CLIENT_CERT environment variable.",
+		)
+	}
+
+	if config.ClientKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_key"),
+			"Unknown Imply Client Key",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the client key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_CLIENT_KEY environment variable.",
+		)
+	}
+
+	if config.InsecureSkipVerify.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure_skip_verify"),
+			"Unknown Imply Insecure Skip Verify",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the insecure_skip_verify setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_INSECURE_SKIP_VERIFY environment variable.",
+		)
+	}
+
+	if config.ProxyURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy_url"),
+			"Unknown Imply Proxy URL",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the proxy URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_PROXY_URL environment variable.",
+		)
+	}
+
+	if config.ReadOnly.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_only"),
+			"Unknown Imply Read Only",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the read_only setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_READ_ONLY environment variable.",
+		)
+	}
+
+	if config.ValidateCredentials.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("validate_credentials"),
+			"Unknown Imply Validate Credentials",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the validate_credentials setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_VALIDATE_CREDENTIALS environment variable.",
+		)
+	}
+
+	if config.ProjectID.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Unknown Imply Project ID",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply project ID. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_PROJECT_ID environment variable.",
+		)
+	}
+
+	if config.Region.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("region"),
+			"Unknown Imply Region",
+			"The provider cannot create the Imply API client as there is an unknown configuration value for the Imply region. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the IMPLY_REGION environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
+	// Select the credentials profile, if any, that settings fall back to.
+
+	profileName := os.Getenv("IMPLY_PROFILE")
+	credentialsFile := os.Getenv("IMPLY_CREDENTIALS_FILE")
+
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+
+	if !config.CredentialsFile.IsNull() {
+		credentialsFile = config.CredentialsFile.ValueString()
+	}
+
+	explicitProfile := profileName != "" || credentialsFile != ""
+	if profileName == "" {
+		profileName = defaultProfile
+	}
+	if credentialsFile == "" {
+		credentialsFile = defaultCredentialsFile()
+	}
+
+	profile, err := loadCredentialsProfile(credentialsFile, profileName, explicitProfile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Load Imply Credentials Profile",
+			"The provider cannot read the selected credentials profile: "+err.Error(),
+		)
+		return
+	}
+
+	// Resolve every setting from the Terraform configuration, then the
+	// environment variables, then the credentials profile.
+
+	sources := newSettingSources(profile)
+
+	// Name the source of every setting whenever the provider cannot be
+	// configured, as a setting can come from a profile or environment
+	// variable the practitioner did not expect.
+	defer func() {
+		if resp.Diagnostics.HasError() && len(sources.sources) > 0 {
+			resp.Diagnostics.AddWarning(
+				"Imply Provider Setting Sources",
+				"The provider settings were read from:\n"+sources.Summary(),
+			)
+		}
+	}()
+
+	// The API key can be given directly, read from a file or printed by a
+	// credential helper. The three are resolved together, so that a key
+	// setting from a higher-precedence source replaces one from a lower
+	// source, and only one of them may be used within a source.
+	keySettings := sources.Exclusive(
+		exclusiveSetting{Name: "api_key", Configured: !config.ApiKey.IsNull(), EnvVar: "IMPLY_API_KEY"},
+		exclusiveSetting{Name: "api_key_file", Configured: !config.ApiKeyFile.IsNull(), EnvVar: "IMPLY_API_KEY_FILE"},
+		exclusiveSetting{Name: "api_key_command", Configured: !config.ApiKeyCommand.IsNull(), EnvVar: "IMPLY_API_KEY_COMMAND"},
+	)
+
+	host := sources.String("host", config.Host, "IMPLY_HOST")
+	apiURL := sources.String("api_url", config.ApiURL, "IMPLY_API_URL")
+	apiKey := sources.String("api_key", config.ApiKey, "IMPLY_API_KEY")
+	apiKeyFile := sources.String("api_key_file", config.ApiKeyFile, "IMPLY_API_KEY_FILE")
+	apiKeyCommand, diags := sources.List(ctx, "api_key_command", config.ApiKeyCommand, "IMPLY_API_KEY_COMMAND")
+	resp.Diagnostics.Append(diags...)
+	projectID := sources.String("project_id", config.ProjectID, "IMPLY_PROJECT_ID")
+	region := sources.String("region", config.Region, "IMPLY_REGION")
+	authMethod := sources.String("auth_method", config.AuthMethod, "IMPLY_AUTH_METHOD")
+	clientID := sources.String("client_id", config.ClientID, "IMPLY_CLIENT_ID")
+	clientSecret := sources.String("client_secret", config.ClientSecret, "IMPLY_CLIENT_SECRET")
+	tokenURL := sources.String("token_url", config.TokenURL, "IMPLY_TOKEN_URL")
+	transport := client.TransportConfig{
+		CACertFile: sources.String("ca_cert_file", config.CACertFile, "IMPLY_CA_CERT_FILE"),
+		CACertPEM:  sources.String("ca_cert_pem", config.CACertPEM, "IMPLY_CA_CERT_PEM"),
+		ClientCert: sources.String("client_cert", config.ClientCert, "IMPLY_CLIENT_CERT"),
+		ClientKey:  sources.String("client_key", config.ClientKey, "IMPLY_CLIENT_KEY"),
+		ProxyURL:   sources.String("proxy_url", config.ProxyURL, "IMPLY_PROXY_URL"),
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		transport.InsecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	} else if value := sources.String("insecure_skip_verify", types.StringNull(), "IMPLY_INSECURE_SKIP_VERIFY"); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure_skip_verify"),
+				"Invalid Insecure Skip Verify",
+				"The insecure_skip_verify value from "+sources.Source("insecure_skip_verify")+" must be a boolean such as \"true\" or \"false\": "+err.Error(),
+			)
+		}
+		transport.InsecureSkipVerify = insecure
+	}
+
+	if len(keySettings) > 1 {
+		keySources := make([]string, 0, len(keySettings))
+		for _, name := range keySettings {
+			keySources = append(keySources, name+" from "+sources.Source(name))
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_key"),
+			"Conflicting Imply API Key Settings",
+			"Only one of api_key, api_key_file and api_key_command can be set in the same place, but the provider found "+
+				strings.Join(keySources, ", ")+".",
+		)
+	}
+
+	if apiKeyFile != "" {
+		content, err := os.ReadFile(apiKeyFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_file"),
+				"Unable to Read Imply API Key File",
+				"The provider cannot read the API key file from "+sources.Source("api_key_file")+": "+err.Error(),
+			)
+		} else if apiKey = strings.TrimSpace(string(content)); apiKey == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_file"),
+				"Empty Imply API Key File",
+				"The API key file "+apiKeyFile+" from "+sources.Source("api_key_file")+" is empty.",
+			)
+		}
+	}
+
+	// read_only is deliberately not read from credentials profiles: a
+	// pipeline that relies on it must state it in its own configuration or
+	// environment.
+	var readOnly bool
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	} else if value := os.Getenv("IMPLY_READ_ONLY"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("read_only"),
+				"Invalid Read Only",
+				"The IMPLY_READ_ONLY environment variable must be a boolean such as \"true\" or \"false\": "+err.Error(),
+			)
+		}
+		readOnly = parsed
+	}
+
+	var validateCredentials bool
+	if !config.ValidateCredentials.IsNull() {
+		validateCredentials = config.ValidateCredentials.ValueBool()
+	} else if value := os.Getenv("IMPLY_VALIDATE_CREDENTIALS"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validate_credentials"),
+				"Invalid Validate Credentials",
+				"The IMPLY_VALIDATE_CREDENTIALS environment variable must be a boolean such as \"true\" or \"false\": "+err.Error(),
+			)
+		}
+		validateCredentials = parsed
+	}
+
+	tflog.Debug(ctx, "Resolved Imply provider settings", map[string]any{
+		"sources": sources.sources,
+	})
+
+	if authMethod == "" {
+		authMethod = authMethodAPIKey
+	}
+
+	maxRetries := client.DefaultMaxRetries
+	retryMaxWait := client.DefaultRetryMaxWait
 
-	host := os.Getenv("IMPLY_HOST")
-	apiKey := os.Getenv("IMPLY_API_KEY")
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if !config.RetryMaxWait.IsNull() && !config.RetryMaxWait.IsUnknown() {
+		wait, err := time.ParseDuration(config.RetryMaxWait.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid Retry Max Wait",
+				"The retry_max_wait value must be a duration such as \"30s\" or \"2m\": "+err.Error(),
+			)
+		} else {
+			retryMaxWait = wait
+		}
+	}
 
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	var requestsPerSecond float64
+	var maxConcurrentRequests int
+
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() {
+		requestsPerSecond = config.RequestsPerSecond.ValueFloat64()
 	}
 
-	if !config.ApiKey.IsNull() {
-		apiKey = config.ApiKey.ValueString()
+	if !config.MaxConcurrentRequests.IsNull() && !config.MaxConcurrentRequests.IsUnknown() {
+		maxConcurrentRequests = int(config.MaxConcurrentRequests.ValueInt64())
 	}
 
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
-	if host == "" {
+	if host == "" && apiURL == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Missing Imply API Host",
 			"The provider cannot create the Imply API client as there is a missing or empty value for the Imply API host. "+
-				"Set the host value in the configuration or use the IMPLY_HOST environment variable. "+
+				"Set the host or api_url value in the configuration or credentials profile, or use the IMPLY_HOST or IMPLY_API_URL environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if apiKey == "" {
+	switch authMethod {
+	case authMethodAPIKey:
+		if apiKey == "" && len(apiKeyCommand) == 0 && len(keySettings) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key"),
+				"Missing Imply API Key",
+				"The provider cannot create the Imply API client as there is a missing or empty value for the Imply API key. "+
+					"Set the api_key, api_key_file or api_key_command value in the configuration or credentials profile, "+
+					"or use the IMPLY_API_KEY, IMPLY_API_KEY_FILE or IMPLY_API_KEY_COMMAND environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+
+	case authMethodOAuth:
+		if clientID == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client_id"),
+				"Missing Imply OAuth Client ID",
+				"The provider cannot create the Imply API client as OAuth authentication requires a client ID. "+
+					"Set the client_id value in the configuration or credentials profile, or use the IMPLY_CLIENT_ID environment variable.",
+			)
+		}
+
+		if clientSecret == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client_secret"),
+				"Missing Imply OAuth Client Secret",
+				"The provider cannot create the Imply API client as OAuth authentication requires a client secret. "+
+					"Set the client_secret value in the configuration or credentials profile, or use the IMPLY_CLIENT_SECRET environment variable.",
+			)
+		}
+
+		if tokenURL == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_url"),
+				"Missing Imply OAuth Token URL",
+				"The provider cannot create the Imply API client as OAuth authentication requires a token URL. "+
+					"Set the token_url value in the configuration or credentials profile, or use the IMPLY_TOKEN_URL environment variable.",
+			)
+		}
+
+	default:
 		resp.Diagnostics.AddAttributeError(
-			path.Root("api_key"),
-			"Missing Imply API Key",
-			"The provider cannot create the Imply API client as there is a missing or empty value for the Imply API key. "+
-				"Set the api_key value in the configuration or use the IMPLY_API_KEY environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("auth_method"),
+			"Invalid Imply Authentication Method",
+			fmt.Sprintf("The auth_method value from %s must be %q or %q, got: %q.",
+				sources.Source("auth_method"), authMethodAPIKey, authMethodOAuth, authMethod),
+		)
+	}
+
+	if (transport.ClientCert == "") != (transport.ClientKey == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_key"),
+			"Incomplete Imply Client Certificate",
+			"Mutual TLS requires both client_cert and client_key, or their IMPLY_CLIENT_CERT and IMPLY_CLIENT_KEY environment variables, to be set, "+
+				"but the provider found client_cert from "+sources.Source("client_cert")+" and client_key from "+sources.Source("client_key")+".",
+		)
+	}
+
+	if transport.InsecureSkipVerify {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS Certificate Verification Disabled",
+			"insecure_skip_verify is enabled, so the provider does not verify the Imply API's TLS certificate. "+
+				"Anyone able to intercept the connection can read and alter every request, including the credentials it carries. "+
+				"Trust the intercepting proxy's certificate authority with ca_cert_file or ca_cert_pem instead.",
+		)
+	}
+
+	if maxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid Max Retries",
+			"The max_retries value cannot be negative.",
+		)
+	}
+
+	if retryMaxWait <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_max_wait"),
+			"Invalid Retry Max Wait",
+			"The retry_max_wait value must be a positive duration.",
+		)
+	}
+
+	if requestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid Requests Per Second",
+			"The requests_per_second value cannot be negative.",
+		)
+	}
+
+	if maxConcurrentRequests < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_requests"),
+			"Invalid Max Concurrent Requests",
+			"The max_concurrent_requests value cannot be negative.",
 		)
 	}
 
@@ -142,8 +719,41 @@ func (p *implyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
+	opts := []client.Option{
+		client.WithRetry(maxRetries, retryMaxWait),
+		client.WithRateLimit(requestsPerSecond, maxConcurrentRequests),
+		client.WithReadOnly(readOnly),
+		client.WithUserAgent(p.version, req.TerraformVersion),
+		client.WithProject(projectID, region),
+		client.WithAPIURL(apiURL),
+		client.WithTransport(transport),
+	}
+
+	if authMethod == authMethodAPIKey && len(apiKeyCommand) > 0 {
+		// Run the helper now so that a broken one is reported once, at
+		// configure time, rather than by every resource.
+		auth := &client.APIKeyCommandAuth{Command: apiKeyCommand}
+		if _, err := auth.Authorization(ctx); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_command"),
+				"Unable to Run Imply API Key Command",
+				"The provider cannot obtain an API key from the command from "+sources.Source("api_key_command")+": "+err.Error(),
+			)
+			return
+		}
+		opts = append(opts, client.WithAuthenticator(auth))
+	}
+
+	if authMethod == authMethodOAuth {
+		opts = append(opts, client.WithAuthenticator(&client.OAuthClientCredentials{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+		}))
+	}
+
 	// Create a new imply client using the configuration values
-	client, err := client.NewClient(&host, &apiKey)
+	client, err := client.NewClient(&host, &apiKey, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Imply API Client",
@@ -154,12 +764,52 @@ func (p *implyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
+	if validateCredentials && !checkCredentials(ctx, client, &resp.Diagnostics) {
+		return
+	}
+
 	// Make the imply client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// checkCredentials validates the client's credentials, adding an error
+// diagnostic and returning false when they are rejected.
+func checkCredentials(ctx context.Context, c *client.Client, diags *diag.Diagnostics) bool {
+	info, err := c.ValidateCredentials(ctx)
+	switch {
+	case err == nil:
+		tflog.Info(ctx, "Validated Imply credentials", map[string]any{
+			"key_id":      info.ID,
+			"permissions": info.Permissions,
+		})
+		return true
+	case client.IsUnauthorized(err):
+		diags.AddError(
+			"Invalid Imply Credentials",
+			"The Imply API rejected the configured credentials. Check that the API key or OAuth client is correct, "+
+				"has not been revoked or expired, and belongs to the organization of the configured host.\n\n"+
+				"Imply Client Error: "+err.Error(),
+		)
+	case client.IsForbidden(err):
+		diags.AddError(
+			"Imply Credentials Not Permitted",
+			"The configured credentials are valid but not permitted to read their own details. "+
+				"Grant the API key access, or disable validate_credentials.\n\n"+
+				"Imply Client Error: "+err.Error(),
+		)
+	default:
+		diags.AddError(
+			"Unable to Validate Imply Credentials",
+			"An unexpected error occurred when validating the Imply credentials. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Imply Client Error: "+err.Error(),
+		)
+	}
+	return false
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *implyProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
@@ -167,7 +817,9 @@ func (p *implyProvider) DataSources(_ context.Context) []func() datasource.DataS
 		auth.NewUserDataSource,
 		auth.NewGroupsDataSource,
 		auth.NewGroupDataSource,
+		auth.NewGroupMembersDataSource,
 		auth.NewPermissionsDataSource,
+		auth.NewUserEffectivePermissionsDataSource,
 	}
 }
 
@@ -177,5 +829,7 @@ func (p *implyProvider) Resources(_ context.Context) []func() resource.Resource
 		auth.NewUserResource,
 		auth.NewGroupResource,
 		auth.NewGroupMemberResource,
+		auth.NewGroupMembersResource,
+		auth.NewUserGroupMembershipsResource,
 	}
 }
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+
+package imply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// providerEnvVars are the environment variables the provider reads its
+// settings from.
+var providerEnvVars = []string{
+	"IMPLY_API_KEY", "IMPLY_API_KEY_COMMAND", "IMPLY_API_KEY_FILE", "IMPLY_API_URL",
+	"IMPLY_AUTH_METHOD", "IMPLY_CA_CERT_FILE", "IMPLY_CA_CERT_PEM", "IMPLY_CLIENT_CERT",
+	"IMPLY_CLIENT_ID", "IMPLY_CLIENT_KEY", "IMPLY_CLIENT_SECRET", "IMPLY_CREDENTIALS_FILE",
+	"IMPLY_HOST", "IMPLY_INSECURE_SKIP_VERIFY", "IMPLY_PROFILE", "IMPLY_PROJECT_ID",
+	"IMPLY_PROXY_URL", "IMPLY_READ_ONLY", "IMPLY_REGION", "IMPLY_TOKEN_URL",
+	"IMPLY_VALIDATE_CREDENTIALS",
+}
+
+// configureProvider configures the provider with an empty configuration and
+// the given environment, and returns its diagnostics.
+func configureProvider(t *testing.T, env map[string]string) diag.Diagnostics {
+	t.Helper()
+
+	for _, envVar := range providerEnvVars {
+		t.Setenv(envVar, env[envVar])
+	}
+
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, values)},
+	}, &resp)
+
+	return resp.Diagnostics
+}
+
+func TestConfigureNamesSettingSources(t *testing.T) {
+	credentials := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(credentials, []byte("[default]\nhost = example.api.imply.io\nauth_method = saml\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	diags := configureProvider(t, map[string]string{
+		"IMPLY_CREDENTIALS_FILE": credentials,
+		"IMPLY_API_KEY":          "pok_env",
+	})
+	if !diags.HasError() {
+		t.Fatal("Configure succeeded, want an invalid auth_method error")
+	}
+
+	profileSource := `profile "default" in ` + credentials
+	var authMethodError string
+	for _, d := range diags.Errors() {
+		if d.Summary() == "Invalid Imply Authentication Method" {
+			authMethodError = d.Detail()
+		}
+	}
+	if !strings.Contains(authMethodError, "auth_method value from "+profileSource) {
+		t.Errorf("auth_method error = %q, want it to name %s", authMethodError, profileSource)
+	}
+
+	var sources string
+	for _, d := range diags.Warnings() {
+		if d.Summary() == "Imply Provider Setting Sources" {
+			sources = d.Detail()
+		}
+	}
+	for _, want := range []string{
+		"- api_key: the IMPLY_API_KEY environment variable",
+		"- auth_method: " + profileSource,
+		"- host: " + profileSource,
+	} {
+		if !strings.Contains(sources, want) {
+			t.Errorf("setting sources = %q, want a line %q", sources, want)
+		}
+	}
+	if strings.Contains(sources, "pok_env") {
+		t.Errorf("setting sources = %q, want no setting values", sources)
+	}
+}
+
+func TestConfigureWithoutErrorsOmitsSettingSources(t *testing.T) {
+	// Keep a credentials file in the real home directory out of the test.
+	t.Setenv("HOME", t.TempDir())
+
+	diags := configureProvider(t, map[string]string{
+		"IMPLY_HOST":    "example.api.imply.io",
+		"IMPLY_API_KEY": "pok_env",
+	})
+
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("Configure diagnostics = %v, want none", diags)
+	}
+}